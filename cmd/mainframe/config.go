@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"mainframe/pkg/config"
+	"os"
+	"text/tabwriter"
+)
+
+func runConfig(args []string, overrides map[string]string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: mainframe config get <key> | set <key> <value> | list | doctor [--dry-run]")
+		return 2
+	}
+
+	switch args[0] {
+	case "get":
+		return runConfigGet(args[1:], overrides)
+	case "set":
+		return runConfigSet(args[1:], overrides)
+	case "list":
+		return runConfigList(args[1:], overrides)
+	case "doctor":
+		return runDoctor(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown config command %q\n", args[0])
+		return 2
+	}
+}
+
+// runConfigGet prints the effective value of one setting
+func runConfigGet(args []string, overrides map[string]string) int {
+	fs := flag.NewFlagSet("config get", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the value as JSON")
+	args, err := parseCommand(fs, args)
+	if err != nil {
+		return usageCode(err)
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: mainframe config get <key> [--json]")
+		return 2
+	}
+
+	cfg := loadConfig(overrides)
+	value, err := cfg.Get(args[0])
+	if err != nil {
+		return fail(*asJSON, "config get", err)
+	}
+
+	if *asJSON {
+		printJSON(map[string]string{"key": args[0], "value": value, "source": cfg.Source(args[0]).String()})
+	} else {
+		fmt.Println(value)
+	}
+	return 0
+}
+
+// runConfigSet changes one setting in the user file. Settings that live
+// in a profile are changed in the active profile.
+func runConfigSet(args []string, overrides map[string]string) int {
+	fs := flag.NewFlagSet("config set", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	args, err := parseCommand(fs, args)
+	if err != nil {
+		return usageCode(err)
+	}
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: mainframe config set <key> <value> [--json]")
+		return 2
+	}
+	key, value := args[0], args[1]
+
+	cfg := loadConfig(overrides)
+	if err := cfg.Set(key, value); err != nil {
+		return fail(*asJSON, "config set", fmt.Errorf("%s: %w", key, err))
+	}
+	if err := config.Save(cfg); err != nil {
+		return fail(*asJSON, "config set", err)
+	}
+
+	// Env and flags still win over the user file on the next run
+	source := config.LayerUser
+	if s := cfg.Source(key); s > source {
+		source = s
+		fmt.Fprintf(os.Stderr, "warning: %s is overridden by the %s layer\n", key, s)
+	}
+
+	if *asJSON {
+		printJSON(map[string]string{"key": key, "value": masked(key, value), "file": cfg.File(), "source": source.String()})
+	}
+	return 0
+}
+
+// runConfigList prints every effective setting and the layer it came from
+func runConfigList(args []string, overrides map[string]string) int {
+	fs := flag.NewFlagSet("config list", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the settings as JSON")
+	if _, err := parseCommand(fs, args); err != nil {
+		return usageCode(err)
+	}
+
+	cfg := loadConfig(overrides)
+
+	type setting struct {
+		Key    string `json:"key"`
+		Value  string `json:"value"`
+		Source string `json:"source"`
+	}
+	var settings []setting
+	for _, f := range config.Fields() {
+		value, _ := cfg.Get(f.Key)
+		settings = append(settings, setting{f.Key, masked(f.Key, value), cfg.Source(f.Key).String()})
+	}
+
+	if *asJSON {
+		printJSON(map[string]any{
+			"settings":    settings,
+			"system_file": config.SystemPath,
+			"user_file":   cfg.File(),
+		})
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, s := range settings {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
+	}
+	w.Flush()
+
+	fmt.Printf("\nsystem file: %s\nuser file:   %s\n", config.SystemPath, cfg.File())
+	return 0
+}
+
+// masked hides the value of a secret setting such as api_key
+func masked(key, value string) string {
+	for _, f := range config.Fields() {
+		if f.Key == key && f.Secret && value != "" {
+			return "********"
+		}
+	}
+	return value
+}
+
+// runDoctor checks the config files and repairs what it can
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "report problems without repairing them")
+	asJSON := fs.Bool("json", false, "print the problems as JSON")
+	if _, err := parseCommand(fs, args); err != nil {
+		return usageCode(err)
+	}
+
+	problems, err := config.Doctor(!*dryRun)
+	code := 0
+	for _, p := range problems {
+		if !p.Fixed {
+			code = 1
+		}
+	}
+	if err != nil {
+		code = 1
+	}
+
+	if *asJSON {
+		out := map[string]any{"file": config.Path(), "problems": problems, "ok": code == 0}
+		if problems == nil {
+			out["problems"] = []config.Problem{}
+		}
+		if err != nil {
+			out["error"] = err.Error()
+		}
+		printJSON(out)
+		return code
+	}
+
+	for _, p := range problems {
+		status := "found"
+		if p.Fixed {
+			status = "fixed"
+		}
+		fmt.Printf("[%s] %s\n        fix: %s\n", status, p.Description, p.Fix)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "doctor: %v\n", err)
+		return 1
+	}
+	if len(problems) == 0 {
+		fmt.Printf("%s: no problems found\n", config.Path())
+	}
+	return code
+}
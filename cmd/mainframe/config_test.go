@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestConfigSetMasksAPIKey(t *testing.T) {
+	isolate(t)
+	key := "sk-" + strings.Repeat("x", 40)
+	var code int
+	out := stdout(t, func() {
+		code = runConfigSet([]string{"api_key", key, "--json"}, nil)
+	})
+	if code != 0 {
+		t.Fatalf("exit code %d", code)
+	}
+	if strings.Contains(out, key) {
+		t.Errorf("API key printed in clear:\n%s", out)
+	}
+	var got map[string]string
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["key"] != "api_key" || got["value"] != "********" {
+		t.Errorf("output %v, want the value masked", got)
+	}
+}
+
+func TestHelpExitsZero(t *testing.T) {
+	for _, arg := range []string{"-h", "--help"} {
+		_, _, _, err := parseGlobalFlags([]string{arg})
+		if code := usageCode(err); code != 0 {
+			t.Errorf("mainframe %s exits %d, want 0", arg, code)
+		}
+		if code := runConfigList([]string{arg}, nil); code != 0 {
+			t.Errorf("mainframe config list %s exits %d, want 0", arg, code)
+		}
+	}
+	_, _, _, err := parseGlobalFlags([]string{"--no-such-flag"})
+	if code := usageCode(err); code != 2 {
+		t.Errorf("an unknown flag exits %d, want 2", code)
+	}
+}
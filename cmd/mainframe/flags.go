@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"mainframe/pkg/config"
+	"mainframe/pkg/logging"
+	"os"
+)
+
+// overrideValue records a config setting given on the command line
+type overrideValue struct {
+	key       string
+	bool      bool
+	overrides map[string]string
+}
+
+func (v *overrideValue) String() string { return "" }
+
+func (v *overrideValue) Set(s string) error {
+	v.overrides[v.key] = s
+	return nil
+}
+
+func (v *overrideValue) IsBoolFlag() bool { return v.bool }
+
+// parseGlobalFlags reads the flags that come before any subcommand. Every
+// config field can be overridden as --<key>, and the overrides are
+// returned keyed by config key together with the --record file and the
+// remaining arguments.
+func parseGlobalFlags(args []string) (map[string]string, string, []string, error) {
+	overrides := map[string]string{}
+	fs := flag.NewFlagSet("mainframe", flag.ContinueOnError)
+	record := fs.String("record", "", "record the TUI session to this file for `mainframe replay`")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: mainframe [flags] [command]")
+		fmt.Fprint(fs.Output(), `
+commands:
+  (none)                          start the TUI
+  config get <key>                print a setting
+  config set <key> <value>        change a setting in the user file
+  config list                     print every setting and where it came from
+  model list                      list known local models
+  model download <name> [--use]   download a model into the models directory
+  model test [--path <weights>]   check local model weights
+  doctor [--dry-run]              check and repair the config files
+  version                         print version information
+  serve [--addr :2222]            serve the TUI over SSH
+  instructor [--http <addr>]      open the instructor dashboard
+  replay [--final|--play] <file>  draw the frames of a session saved with --record
+
+config, model, doctor and version accept --json for machine-readable output.
+
+flags:
+`)
+		fs.PrintDefaults()
+	}
+
+	for _, f := range config.Fields() {
+		fs.Var(&overrideValue{key: f.Key, bool: f.Bool, overrides: overrides}, f.Flag, f.Description)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, "", nil, err
+	}
+	return overrides, *record, fs.Args(), nil
+}
+
+// loadConfig loads the user's config and starts logging as it asks
+func loadConfig(overrides map[string]string) *config.Config {
+	cfg, err := config.Load(overrides)
+	if lerr := logging.Apply(cfg.LogsDir(), cfg.Profile().Logs, cfg.Profile().Debug); lerr != nil {
+		fmt.Fprintf(os.Stderr, "warning: not writing logs: %v\n", lerr)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		slog.Warn("loading config", "err", err)
+	}
+	return cfg
+}
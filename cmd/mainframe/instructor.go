@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"mainframe/internal/ui"
+	"mainframe/pkg/presence"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runInstructor opens the instructor dashboard and, unless disabled,
+// serves the same presence data as JSON on a loopback address
+func runInstructor(args []string, overrides map[string]string) int {
+	fs := flag.NewFlagSet("instructor", flag.ContinueOnError)
+	addr := fs.String("http", "127.0.0.1:7878", "loopback address for the read-only JSON endpoint (empty to disable)")
+	if err := fs.Parse(args); err != nil {
+		return usageCode(err)
+	}
+
+	cfg := loadConfig(overrides)
+	if *addr != "" {
+		ln, err := listenLoopback(*addr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "instructor: %v\n", err)
+			return 1
+		}
+		defer ln.Close()
+		go http.Serve(ln, presenceHandler(cfg.PresenceDir, cfg.Instructor))
+	}
+
+	model := ui.NewAppModel(cfg, ui.AppOptions{Instructor: true})
+	var popts []tea.ProgramOption
+	if !cfg.Accessible {
+		popts = append(popts, tea.WithAltScreen())
+	}
+	if _, err := tea.NewProgram(model, popts...).Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "instructor: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// listenLoopback listens on addr, refusing anything but a loopback
+// address since the endpoint has no authentication
+func listenLoopback(addr string) (net.Listener, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("%s is not a loopback address", addr)
+	}
+	return net.Listen("tcp", addr)
+}
+
+// presenceHandler serves GET /presence with the live heartbeats and the
+// current broadcast
+func presenceHandler(dir, instructor string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/presence", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		beats, err := presence.Read(dir, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		broadcast, err := presence.Latest(dir, instructor)
+		var refused string
+		if err != nil {
+			refused = err.Error()
+		}
+		if beats == nil {
+			beats = []presence.Heartbeat{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Trainees       []presence.Heartbeat `json:"trainees"`
+			Broadcast      *presence.Broadcast  `json:"broadcast"`
+			BroadcastError string               `json:"broadcast_error,omitempty"`
+		}{beats, broadcast, refused})
+	})
+	return mux
+}
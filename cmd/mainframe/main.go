@@ -1,19 +1,97 @@
 package main
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"mainframe/internal/ui"
+	"mainframe/pkg/config"
+	"mainframe/pkg/logging"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	p := tea.NewProgram(
-		ui.NewHomeModel(),
-		tea.WithAltScreen(),
-	)
+	overrides, record, args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		os.Exit(usageCode(err))
+	}
+
+	if len(args) > 0 {
+		if record != "" {
+			fmt.Fprintln(os.Stderr, "--record only applies to the TUI")
+			os.Exit(2)
+		}
+		switch args[0] {
+		case "config":
+			os.Exit(runConfig(args[1:], overrides))
+		case "model":
+			os.Exit(runModel(args[1:], overrides))
+		case "doctor":
+			os.Exit(runDoctor(args[1:]))
+		case "version":
+			os.Exit(runVersion(args[1:]))
+		case "serve":
+			os.Exit(runServe(args[1:], overrides))
+		case "instructor":
+			os.Exit(runInstructor(args[1:], overrides))
+		case "replay":
+			os.Exit(runReplay(args[1:]))
+		default:
+			fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+			os.Exit(2)
+		}
+	}
+
+	os.Exit(runTUI(overrides, record))
+}
+
+// runTUI runs the app in the terminal until the user quits, recording the
+// session to the record file if one is given
+func runTUI(overrides map[string]string, record string) int {
+	cfg := loadConfig(overrides)
+	defer logging.Close()
+
+	watcher, err := config.Watch()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: not watching config for changes: %v\n", err)
+		slog.Warn("not watching config for changes", "err", err)
+	} else {
+		defer watcher.Close()
+	}
+
+	opts := ui.AppOptions{Watcher: watcher, Logging: true}
+	if record != "" {
+		f, err := os.Create(record)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error recording session: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		opts.Record = f
+		slog.Info("recording session", "file", record)
+	}
+
+	model := ui.NewAppModel(cfg, opts)
+	defer model.Close()
+	p := tea.NewProgram(model, screenOptions(cfg)...)
 
+	slog.Info("starting", "version", version, "profile", cfg.ActiveProfile)
 	if _, err := p.Run(); err != nil {
-		log.Fatalf("Error starting app: %v", err)
+		slog.Error("app stopped", "err", err)
+		fmt.Fprintf(os.Stderr, "Error starting app: %v\n", err)
+		return 1
+	}
+	slog.Info("exiting")
+	return 0
+}
+
+// screenOptions runs the TUI full screen with the mouse, except in
+// accessible mode, where output scrolls in the normal screen so screen
+// readers can follow it
+func screenOptions(cfg *config.Config) []tea.ProgramOption {
+	if cfg.Accessible {
+		return nil
 	}
+	return []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
 }
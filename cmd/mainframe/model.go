@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"mainframe/pkg/config"
+	"mainframe/pkg/model"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+)
+
+func runModel(args []string, overrides map[string]string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: mainframe model list | download <name> [--use] | test [--path <weights>] [--server <url>]")
+		return 2
+	}
+
+	switch args[0] {
+	case "list":
+		return runModelList(args[1:], overrides)
+	case "download":
+		return runModelDownload(args[1:], overrides)
+	case "test":
+		return runModelTest(args[1:], overrides)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown model command %q\n", args[0])
+		return 2
+	}
+}
+
+// runModelList prints the model catalog and which models are on disk
+func runModelList(args []string, overrides map[string]string) int {
+	fs := flag.NewFlagSet("model list", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the models as JSON")
+	if _, err := parseCommand(fs, args); err != nil {
+		return usageCode(err)
+	}
+
+	cfg := loadConfig(overrides)
+	dir := config.ModelsDir()
+
+	type entry struct {
+		model.Model
+		Path       string `json:"path,omitempty"`
+		Downloaded bool   `json:"downloaded"`
+	}
+	var entries []entry
+	for _, m := range model.Catalog() {
+		entries = append(entries, entry{m, m.Path(dir), m.Downloaded(dir)})
+	}
+
+	if *asJSON {
+		printJSON(map[string]any{
+			"models":     entries,
+			"models_dir": dir,
+			"model_path": cfg.Profile().ModelPath,
+		})
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPARAMS\tLICENSE\tSTATUS")
+	for _, e := range entries {
+		status := "manual download from " + e.Page
+		switch {
+		case e.Downloaded:
+			status = "downloaded"
+		case e.URL != "":
+			status = "available"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Name, e.Params, e.License, status)
+	}
+	w.Flush()
+
+	fmt.Printf("\nmodels dir: %s\nmodel path: %s\n", dir, orNone(cfg.Profile().ModelPath))
+	return 0
+}
+
+// runModelDownload fetches a catalog model into the models directory
+func runModelDownload(args []string, overrides map[string]string) int {
+	fs := flag.NewFlagSet("model download", flag.ContinueOnError)
+	use := fs.Bool("use", false, "set model_path to the downloaded weights")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	args, err := parseCommand(fs, args)
+	if err != nil {
+		return usageCode(err)
+	}
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: mainframe model download <name> [--use] [--json]")
+		return 2
+	}
+
+	m, err := model.Lookup(args[0])
+	if err != nil {
+		return fail(*asJSON, "model download", err)
+	}
+	if m.URL == "" {
+		return fail(*asJSON, "model download", fmt.Errorf("%s has no direct download, get it from %s and run `mainframe config set model_path <file>`", m.Name, m.Page))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	dest := m.Path(config.ModelsDir())
+	var progress func(done, total int64)
+	if !*asJSON {
+		progress = func(done, total int64) {
+			if total > 0 {
+				fmt.Fprintf(os.Stderr, "\r%s: %d%%", m.Name, done*100/total)
+			} else {
+				fmt.Fprintf(os.Stderr, "\r%s: %d MB", m.Name, done>>20)
+			}
+		}
+	}
+	err = model.Download(ctx, m.URL, dest, progress)
+	if progress != nil {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		return fail(*asJSON, "model download", err)
+	}
+
+	if *use {
+		cfg := loadConfig(overrides)
+		cfg.Profile().ModelPath = dest
+		if err := config.Save(cfg); err != nil {
+			return fail(*asJSON, "model download", err)
+		}
+	}
+
+	if *asJSON {
+		printJSON(map[string]any{"name": m.Name, "path": dest, "used": *use})
+	} else {
+		fmt.Println(dest)
+	}
+	return 0
+}
+
+// Exit codes of `model test`, so pipelines can tell what broke
+const (
+	exitModelFile      = 3
+	exitModelFormat    = 4
+	exitModelServer    = 5
+	exitModelInference = 6
+)
+
+// runModelTest runs the same self-test as the Test Model step in the TUI
+// against the configured local model weights, or the ones given with
+// --path
+func runModelTest(args []string, overrides map[string]string) int {
+	fs := flag.NewFlagSet("model test", flag.ContinueOnError)
+	path := fs.String("path", "", "weights to test instead of the configured model_path")
+	server := fs.String("server", "", "model server to test against instead of the configured model_server")
+	timeout := fs.Duration("timeout", 2*time.Minute, "give up after this long")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: mainframe model test [--path <weights>] [--server <url>] [--json]")
+		fmt.Fprintln(fs.Output(), "\nexit codes: 0 passed, 3 file, 4 format, 5 server, 6 inference\n\nflags:")
+		fs.PrintDefaults()
+	}
+	if _, err := parseCommand(fs, args); err != nil {
+		return usageCode(err)
+	}
+
+	cfg := loadConfig(overrides)
+	if *path == "" {
+		*path = cfg.Profile().ModelPath
+	}
+	if *server == "" {
+		*server = cfg.Profile().Server()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	report := model.SelfTest(ctx, *path, model.NewServer(*server))
+
+	if *asJSON {
+		printJSON(report)
+	} else {
+		for _, step := range report.Steps {
+			status, detail := "ok", step.Detail
+			if !step.OK {
+				status, detail = "FAIL", step.Error
+			}
+			fmt.Printf("%-9s %-4s %8.1fms  %s\n", step.Name, status, step.DurationMS, detail)
+		}
+	}
+
+	switch report.Failed {
+	case "":
+		return 0
+	case model.StepFile:
+		return exitModelFile
+	case model.StepFormat:
+		return exitModelFormat
+	case model.StepServer:
+		return exitModelServer
+	default:
+		return exitModelInference
+	}
+}
+
+func orNone(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+	return v
+}
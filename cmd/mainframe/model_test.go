@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"mainframe/pkg/config"
+	"mainframe/pkg/model"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// isolate points the config at an empty scratch directory
+func isolate(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	for _, f := range config.Fields() {
+		t.Setenv(f.Env, "")
+		os.Unsetenv(f.Env)
+	}
+	system := config.SystemPath
+	config.SystemPath = filepath.Join(dir, "system.json")
+	t.Cleanup(func() { config.SystemPath = system })
+}
+
+// stdout runs f and returns what it printed
+func stdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+	f()
+	os.Stdout = orig
+	w.Close()
+	return <-done
+}
+
+func TestModelTestExitCodes(t *testing.T) {
+	isolate(t)
+	dir := t.TempDir()
+	write := func(name, head string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(head+"\x00\x00\x00\x00\x00\x00\x00\x00"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	gguf := write("llama.gguf", "GGUF")
+	pytorch := write("llama.bin", "PK\x03\x04")
+
+	completion := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.Write([]byte(`{"status":"ok"}`))
+		case "/props":
+			w.Write([]byte(`{"model_path":"/models/llama.gguf"}`))
+		case "/completion":
+			w.WriteHeader(completion)
+			w.Write([]byte(`{"content":" Paris","tokens_predicted":8}`))
+		}
+	}))
+	defer srv.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		server     string
+		completion int
+		code       int
+		failed     string
+	}{
+		{"passes", gguf, srv.URL, http.StatusOK, 0, ""},
+		{"missing file", filepath.Join(dir, "missing.gguf"), srv.URL, http.StatusOK, exitModelFile, model.StepFile},
+		{"unsupported format", pytorch, srv.URL, http.StatusOK, exitModelFormat, model.StepFormat},
+		{"server down", gguf, down.URL, http.StatusOK, exitModelServer, model.StepServer},
+		{"inference fails", gguf, srv.URL, http.StatusInternalServerError, exitModelInference, model.StepInference},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completion = tt.completion
+			var code int
+			out := stdout(t, func() {
+				code = runModelTest([]string{"--path", tt.path, "--server", tt.server, "--json"}, nil)
+			})
+			if code != tt.code {
+				t.Errorf("exit code %d, want %d", code, tt.code)
+			}
+			var r model.Report
+			if err := json.Unmarshal([]byte(out), &r); err != nil {
+				t.Fatalf("output is not a report: %v\n%s", err, out)
+			}
+			if r.Failed != tt.failed || r.OK != (tt.failed == "") || r.Path != tt.path {
+				t.Errorf("report %+v, want %q failed", r, tt.failed)
+			}
+		})
+	}
+}
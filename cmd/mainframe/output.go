@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// printJSON writes v to stdout for scripts that pass --json
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// fail reports err on stderr, or as {"error": ...} on stdout in JSON mode,
+// and returns exit code 1
+func fail(asJSON bool, cmd string, err error) int {
+	if asJSON {
+		printJSON(map[string]string{"error": err.Error()})
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd, err)
+	}
+	return 1
+}
+
+// usageCode is the exit code for flags that did not parse: 0 if they
+// asked for help, 2 otherwise
+func usageCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	return 2
+}
+
+// parseCommand parses args with fs, allowing flags after positional
+// arguments as in `config get theme --json`, and returns the positional
+// arguments
+func parseCommand(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
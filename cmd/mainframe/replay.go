@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"mainframe/internal/ui"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// runReplay draws the frames of a session recorded with --record
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	final := fs.Bool("final", false, "print only the last frame")
+	play := fs.Bool("play", false, "redraw the frames in place at the recorded pace")
+	positional, err := parseCommand(fs, args)
+	if err != nil {
+		return usageCode(err)
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: mainframe replay [--final|--play] <session.jsonl>")
+		return 2
+	}
+
+	f, err := os.Open(positional[0])
+	if err != nil {
+		return fail(false, "replay", err)
+	}
+	defer f.Close()
+
+	var last ui.Frame
+	var shown time.Duration
+	n := 0
+	err = ui.Replay(f, lipgloss.NewRenderer(os.Stdout), func(fr ui.Frame) error {
+		n++
+		switch {
+		case *final:
+			last = fr
+		case *play:
+			time.Sleep(fr.Offset - shown)
+			shown = fr.Offset
+			fmt.Print("\x1b[H\x1b[2J" + fr.View)
+		default:
+			fmt.Printf("── frame %d at %s: %s\n%s\n", n, fr.Offset.Round(time.Millisecond), fr.Msg, fr.View)
+		}
+		return nil
+	})
+	if *final && n > 0 {
+		fmt.Println(last.View)
+	}
+	if err != nil {
+		return fail(false, "replay", err)
+	}
+	return 0
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"mainframe/internal/ui"
+	"mainframe/pkg/config"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	bm "github.com/charmbracelet/wish/bubbletea"
+	"github.com/charmbracelet/wish/logging"
+	"github.com/muesli/termenv"
+)
+
+// serveOptions configures the SSH server started by `mainframe serve`
+type serveOptions struct {
+	addr           string
+	dataDir        string
+	authorizedKeys string
+	maxSessions    int
+	perUser        int
+	idleTimeout    time.Duration
+	maxTimeout     time.Duration
+	overrides      map[string]string
+}
+
+// runServe serves one Mainframe TUI per SSH connection. Users must log in
+// with a public key; each key gets its own data directory, so settings and
+// progress follow the trainee rather than the account they log in as.
+func runServe(args []string, overrides map[string]string) int {
+	opts := serveOptions{overrides: overrides}
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.StringVar(&opts.addr, "addr", ":2222", "address to listen on")
+	fs.StringVar(&opts.dataDir, "data-dir", filepath.Join(config.Dir(), "serve"), "where host keys and per-user data are kept")
+	fs.StringVar(&opts.authorizedKeys, "authorized-keys", "", "only accept keys listed in this file (default: accept any key)")
+	fs.IntVar(&opts.maxSessions, "max-sessions", 50, "maximum concurrent sessions")
+	fs.IntVar(&opts.perUser, "max-sessions-per-user", 2, "maximum concurrent sessions per key")
+	fs.DurationVar(&opts.idleTimeout, "idle-timeout", 15*time.Minute, "disconnect sessions idle for this long")
+	fs.DurationVar(&opts.maxTimeout, "max-timeout", 0, "disconnect sessions after this long regardless of activity (0 for no limit)")
+	if err := fs.Parse(args); err != nil {
+		return usageCode(err)
+	}
+
+	if err := serve(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "serve: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func serve(opts serveOptions) error {
+	if err := os.MkdirAll(opts.dataDir, 0700); err != nil {
+		return err
+	}
+
+	auth := wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true })
+	if opts.authorizedKeys != "" {
+		auth = wish.WithAuthorizedKeys(opts.authorizedKeys)
+	}
+
+	// Middlewares run last to first
+	srv, err := wish.NewServer(
+		wish.WithAddress(opts.addr),
+		wish.WithHostKeyPath(filepath.Join(opts.dataDir, "host_ed25519")),
+		auth,
+		wish.WithIdleTimeout(opts.idleTimeout),
+		wish.WithMaxTimeout(opts.maxTimeout),
+		wish.WithMiddleware(
+			bm.MiddlewareWithProgramHandler(opts.program, termenv.ANSI256),
+			limitSessions(opts.maxSessions, opts.perUser),
+			requirePty(),
+			logging.Middleware(),
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, os.Interrupt, syscall.SIGTERM)
+
+	errs := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(os.Stderr, "serving Mainframe on %s\n", opts.addr)
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ssh.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-done:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+// program starts a TUI for one session, loading the config, key bindings,
+// themes and logs kept for the session's public key. Sessions sharing a
+// key watch the config so they pick up each other's saves.
+func (opts serveOptions) program(s ssh.Session) *tea.Program {
+	dir := filepath.Join(opts.dataDir, "users", keyID(s.PublicKey()))
+	cfg, err := config.LoadFrom(dir, opts.overrides)
+	if err != nil {
+		wish.Errorln(s, "warning:", err)
+	}
+	watcher, err := config.WatchFrom(dir)
+	if err != nil {
+		wish.Errorln(s, "warning: not watching config for changes:", err)
+	}
+
+	pty, _, _ := s.Pty()
+	renderer := lipgloss.NewRenderer(s, termenv.WithUnsafe())
+	renderer.SetColorProfile(sessionColorProfile(pty.Term, s.Environ()))
+
+	model := ui.NewAppModel(cfg, ui.AppOptions{Renderer: renderer, Watcher: watcher, User: s.User()})
+	go func() {
+		<-s.Context().Done()
+		model.Close()
+		if watcher != nil {
+			watcher.Close()
+		}
+	}()
+	return tea.NewProgram(model, append(screenOptions(cfg), tea.WithInput(s), tea.WithOutput(s))...)
+}
+
+// keyID names the data directory of a public key
+func keyID(key ssh.PublicKey) string {
+	sum := sha256.Sum256(key.Marshal())
+	return hex.EncodeToString(sum[:])
+}
+
+// sessionColorProfile guesses the client's color support from what it
+// tells us about its terminal, since it cannot be queried over SSH
+func sessionColorProfile(term string, environ []string) termenv.Profile {
+	for _, kv := range environ {
+		if kv == "COLORTERM=truecolor" || kv == "COLORTERM=24bit" {
+			return termenv.TrueColor
+		}
+		if strings.HasPrefix(kv, "NO_COLOR=") && kv != "NO_COLOR=" {
+			return termenv.Ascii
+		}
+	}
+	switch {
+	case strings.Contains(term, "256color"):
+		return termenv.ANSI256
+	case term == "" || term == "dumb":
+		return termenv.Ascii
+	}
+	return termenv.ANSI
+}
+
+// requirePty turns away connections that did not ask for a terminal, such
+// as `ssh lab -p 2222 ls`
+func requirePty() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			if _, _, ok := s.Pty(); !ok {
+				wish.Fatalln(s, "Mainframe needs an interactive terminal, connect with ssh -t")
+				return
+			}
+			next(s)
+		}
+	}
+}
+
+// limitSessions caps concurrent sessions overall and per public key
+func limitSessions(total, perUser int) wish.Middleware {
+	var mu sync.Mutex
+	active := 0
+	byUser := map[string]int{}
+
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			id := keyID(s.PublicKey())
+
+			mu.Lock()
+			if total > 0 && active >= total {
+				mu.Unlock()
+				wish.Fatalln(s, "The lab is full, try again later.")
+				return
+			}
+			if perUser > 0 && byUser[id] >= perUser {
+				mu.Unlock()
+				wish.Fatalf(s, "You already have %d sessions open.\n", byUser[id])
+				return
+			}
+			active++
+			byUser[id]++
+			mu.Unlock()
+
+			defer func() {
+				mu.Lock()
+				active--
+				if byUser[id]--; byUser[id] == 0 {
+					delete(byUser, id)
+				}
+				mu.Unlock()
+			}()
+			next(s)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+// runVersion prints the version and what the binary was built from
+func runVersion(args []string) int {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the version as JSON")
+	if _, err := parseCommand(fs, args); err != nil {
+		return usageCode(err)
+	}
+
+	info := map[string]string{
+		"version":  version,
+		"go":       runtime.Version(),
+		"platform": runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info["commit"] = s.Value
+			case "vcs.time":
+				info["built"] = s.Value
+			}
+		}
+	}
+
+	if *asJSON {
+		printJSON(info)
+		return 0
+	}
+	fmt.Printf("mainframe %s (%s, %s)\n", info["version"], info["platform"], info["go"])
+	if info["commit"] != "" {
+		fmt.Printf("commit %s %s\n", info["commit"], info["built"])
+	}
+	return 0
+}
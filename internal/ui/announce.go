@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"mainframe/pkg/config"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// announcement is one thing a screen reader user is told about when it
+// changes, such as the screen or a setting
+type announcement struct {
+	label string
+	value string
+}
+
+// settingLabels are the names the menus use for settings, where they
+// differ from the config key
+var settingLabels = map[string]string{
+	"debug":        "Debug Mode",
+	"logs":         "Log Output",
+	"experimental": "Experimental",
+}
+
+// announcements describes what the user can see and change right now
+func (m *AppModel) announcements() []announcement {
+	screen, selected := m.T(screenName(m.screen)), m.T(screenActivity(m.screen))
+	switch {
+	case m.conflict != nil:
+		screen, selected = m.T("Config Changed On Disk"), ""
+	case m.presence.broadcast != nil:
+		screen, selected = m.T("Message From %s", m.presence.broadcast.From), ""
+	case m.palette.open:
+		screen, selected = m.T("Command Palette"), ""
+		if pal := m.palette; pal.cursor < len(pal.matches) {
+			selected = pal.matches[pal.cursor].title
+		}
+	}
+	list := []announcement{{m.T("Screen"), screen}, {m.T("Selected"), selected}}
+
+	for _, f := range config.Fields() {
+		if f.Secret {
+			continue
+		}
+		v, _ := m.config.Get(f.Key)
+		if f.Bool {
+			v = m.T(onOffText(v == "true"))
+		}
+		label := f.Key
+		if l, ok := settingLabels[f.Key]; ok {
+			label = m.T(l)
+		}
+		list = append(list, announcement{label, v})
+	}
+	for _, f := range config.Features() {
+		list = append(list, announcement{f.Name, m.T(onOffText(m.config.Enabled(f.Name, m.Now())))})
+	}
+	return append(list, announcement{m.T("Error"), m.errorMsg})
+}
+
+// announce prints a line for everything that changed since the last call
+// when output is linear. The lines go above the view, so they are only
+// seen outside the alternate screen, which linear output does not use.
+func (m *AppModel) announce() tea.Cmd {
+	if !m.Styles().Accessible() {
+		m.announced = nil
+		return nil
+	}
+	now := m.announcements()
+	before := m.announced
+	m.announced = now
+	if before == nil {
+		return nil
+	}
+
+	var lines []string
+	for i, a := range now {
+		if i < len(before) && before[i].value == a.value || a.value == "" {
+			continue
+		}
+		lines = append(lines, a.label+": "+a.value)
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return tea.Println(strings.Join(lines, "\n"))
+}
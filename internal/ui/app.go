@@ -0,0 +1,361 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mainframe/pkg/config"
+	"mainframe/pkg/i18n"
+	"mainframe/pkg/keys"
+	"mainframe/pkg/logging"
+	"mainframe/pkg/styles"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// configChangedMsg is sent when a config file is modified on disk
+type configChangedMsg struct{}
+
+// AppOptions configures one session of the app
+type AppOptions struct {
+	// Renderer draws the session; the default renderer is used if nil
+	Renderer *lipgloss.Renderer
+	// Watcher reports config changes on disk; nil disables reloading
+	Watcher *config.Watcher
+	// User names the person at the keyboard in presence heartbeats
+	User string
+	// Instructor starts on the instructor dashboard instead of the home
+	// screen and does not publish heartbeats itself
+	Instructor bool
+	// Logging lets the session's log settings control the process-wide
+	// logger, which SSH sessions sharing a process must not do
+	Logging bool
+	// Record receives every message the session handles, one JSON line
+	// each, for replaying later
+	Record io.Writer
+	// Clock replaces time.Now for the session
+	Clock func() time.Time
+}
+
+// AppModel is the root model. It hosts the current screen, forwards
+// messages to it and handles concerns shared by every screen, such as
+// picking up config changes made outside the TUI.
+type AppModel struct {
+	BaseModel
+	screen   tea.Model
+	config   *config.Config
+	colors   string
+	watcher  *config.Watcher
+	conflict *config.Conflict
+	errorMsg string
+	presence presenceState
+	logging  bool
+	log      *slog.Logger
+	debug    debugState
+	palette  paletteState
+	recorder *recorder
+	// announced is what announce last told the user about
+	announced []announcement
+}
+
+// NewAppModel starts a session of the app on the home screen
+func NewAppModel(cfg *config.Config, opts AppOptions) *AppModel {
+	m := &AppModel{
+		config:  cfg,
+		watcher: opts.Watcher,
+		logging: opts.Logging,
+		log:     logging.For("ui"),
+		debug:   newDebugState(cfg),
+	}
+	m.clock = opts.Clock
+	m.zones = newZones()
+	km, keysErr := keys.Load(cfg.KeysPath())
+	m.keys = km
+	r := opts.Renderer
+	if r == nil {
+		r = lipgloss.DefaultRenderer()
+	}
+	m.UseStyles(styles.New(r))
+	m.locale = i18n.New(cfg.Language)
+	if opts.Instructor {
+		m.setScreen(NewInstructorModel(cfg, opts.User))
+	} else {
+		m.setScreen(NewHomeModel(cfg))
+		m.presence.err = m.startPresence(opts.User)
+	}
+
+	st := m.Styles()
+	m.colors = cfg.Color
+	err := errors.Join(
+		m.presence.err,
+		keysErr,
+		st.LoadThemes(cfg.ThemesDir()),
+		st.SetBackgroundMode(cfg.Background),
+		st.SetColorMode(cfg.Color),
+		m.applyStyles(),
+	)
+
+	// The recording starts with the keys, language and styles just set up
+	if opts.Record != nil {
+		var recordErr error
+		m.recorder, recordErr = newRecorder(opts.Record, m)
+		err = errors.Join(err, recordErr)
+	}
+	m.setError(err)
+	m.announce()
+	return m
+}
+
+// setScreen makes s the current screen, sharing the session's styles,
+// keys, language, metrics, clock and mouse zones with it
+func (m *AppModel) setScreen(s tea.Model) {
+	if sc, ok := s.(screen); ok {
+		sc.base().UseStyles(m.Styles())
+		sc.base().keys = m.Keys()
+		sc.base().locale = m.Locale()
+		sc.base().metrics = m.Metrics()
+		sc.base().clock = m.clock
+		sc.base().zones = m.zones
+	}
+	m.screen = s
+}
+
+// applyStyles rebuilds the styles from the configured theme and color
+// settings, falling back to the default theme if the chosen one does not
+// exist
+func (m *AppModel) applyStyles() error {
+	st := m.Styles()
+	var errs []error
+	if err := st.SetTheme(m.config.Theme); err != nil {
+		errs = append(errs, err)
+		st.SetTheme(styles.DefaultTheme)
+	}
+
+	// Detecting the background queries the terminal, which is only safe
+	// before the program starts reading input, so a change to auto while
+	// running keeps the current palette
+	if m.config.Color != m.colors {
+		m.colors = m.config.Color
+		errs = append(errs, st.SetColorMode(m.colors))
+	}
+	if bg := m.config.Background; bg == styles.BackgroundDark || bg == styles.BackgroundLight {
+		st.SetBackgroundMode(bg)
+	}
+	st.SetAccessible(m.config.Accessible)
+	return errors.Join(errs...)
+}
+
+func (m *AppModel) Init() tea.Cmd {
+	return tea.Batch(m.screen.Init(), m.waitForConfigChange(), m.heartbeat())
+}
+
+// Close releases what the session holds outside the process
+func (m *AppModel) Close() {
+	m.stopPresence()
+}
+
+func (m *AppModel) waitForConfigChange() tea.Cmd {
+	if m.watcher == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, ok := <-m.watcher.Changes(); !ok {
+			return nil
+		}
+		return configChangedMsg{}
+	}
+}
+
+func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if err := m.recorder.record(m, msg); err != nil {
+		m.recorder = nil
+		m.setError(fmt.Errorf("recording stopped: %w", err))
+	}
+	start := m.Now()
+	model, cmd := m.update(msg)
+	m.debug.record(msg, start, m.Now().Sub(start))
+	return model, tea.Batch(cmd, m.announce())
+}
+
+func (m *AppModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.UpdateSize(msg.Width, msg.Height)
+		return m.forward(WindowSizeMsg{Width: msg.Width, Height: msg.Height})
+
+	case configChangedMsg:
+		conflict, err := m.config.Reload()
+		m.conflict = conflict
+		if conflict != nil {
+			m.log.Warn("config changed on disk with unsaved changes", "file", m.config.File(), "changes", len(conflict.Changes))
+		} else {
+			m.log.Info("config reloaded", "file", m.config.File())
+		}
+		m.Locale().Set(m.config.Language)
+		m.setError(errors.Join(err, m.applyStyles(), m.applyLogging()))
+		return m, m.waitForConfigChange()
+
+	case heartbeatMsg:
+		m.publishHeartbeat()
+		return m, m.heartbeat()
+
+	case tea.KeyMsg:
+		m.presence.lastInput = m.Now()
+		if m.config.Profile().Debug && key.Matches(msg, m.Keys().Debug) {
+			m.debug.visible = !m.debug.visible
+			return m, nil
+		}
+		if m.conflict != nil {
+			return m.resolve(msg)
+		}
+		if m.presence.broadcast != nil {
+			return m.dismissBroadcast(msg)
+		}
+		if m.palette.open {
+			return m.updatePalette(msg)
+		}
+		if key.Matches(msg, m.Keys().Palette) {
+			return m, m.openPalette()
+		}
+
+	case tea.MouseMsg:
+		m.presence.lastInput = m.Now()
+		// The dialogs drawn over the screen take no clicks
+		if m.conflict != nil || m.presence.broadcast != nil || m.palette.open {
+			return m, nil
+		}
+	}
+
+	return m.forward(msg)
+}
+
+// forward passes msg to the current screen and follows it when it
+// navigates elsewhere
+func (m *AppModel) forward(msg tea.Msg) (tea.Model, tea.Cmd) {
+	start := m.Now()
+	next, cmd := m.screen.Update(msg)
+	m.Metrics().Update(fmt.Sprintf("%T", msg), m.Now().Sub(start))
+	if next == m.screen {
+		// Developer Options toggles logs and debug in place
+		switch msg.(type) {
+		case tea.KeyMsg, tea.MouseMsg:
+			if err := m.applyLogging(); err != nil {
+				m.setError(err)
+			}
+		}
+		return m, cmd
+	}
+
+	return m, tea.Batch(cmd, m.navigate(next))
+}
+
+// navigate makes next the current screen, starts it and tells it the
+// window size
+func (m *AppModel) navigate(next tea.Model) tea.Cmd {
+	m.log.Debug("screen changed", "from", screenName(m.screen), "to", screenName(next))
+	m.setScreen(next)
+	next, sizeCmd := m.screen.Update(WindowSizeMsg{Width: m.width, Height: m.height})
+	m.setScreen(next)
+	return tea.Batch(m.screen.Init(), sizeCmd)
+}
+
+func (m *AppModel) resolve(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var how config.Resolution
+	k := m.Keys()
+	switch {
+	case key.Matches(msg, k.Merge):
+		how = config.ResolveMerge
+	case key.Matches(msg, k.Mine):
+		how = config.ResolveMine
+	case key.Matches(msg, k.Theirs):
+		how = config.ResolveTheirs
+	case key.Matches(msg, k.Quit):
+		return m, tea.Quit
+	default:
+		return m, nil
+	}
+
+	m.conflict = nil
+	m.log.Info("config conflict resolved", "how", msg.String())
+	m.setError(errors.Join(m.config.Resolve(how), m.applyStyles(), m.applyLogging()))
+	m.Locale().Set(m.config.Language)
+	return m, nil
+}
+
+// applyLogging follows the debug and log toggles of the active profile
+// when the session owns the logger
+func (m *AppModel) applyLogging() error {
+	if !m.logging {
+		return nil
+	}
+	p := m.config.Profile()
+	return logging.Apply(m.config.LogsDir(), p.Logs, p.Debug)
+}
+
+func (m *AppModel) setError(err error) {
+	if err != nil && err.Error() != m.errorMsg {
+		m.log.Error("config", "err", err)
+		m.debug.recordError(m.Now(), err)
+	}
+	m.errorMsg = ""
+	if err != nil {
+		m.errorMsg = err.Error()
+	}
+}
+
+func (m *AppModel) View() string {
+	st := m.Styles()
+	view := st.Plain(m.zones.scan(m.view()))
+	if m.debug.visible && m.config.Profile().Debug {
+		view = overlay(view, m.debugView(max(m.width/2-6, 20)), m.width)
+	}
+	return view
+}
+
+func (m *AppModel) view() string {
+	st := m.Styles()
+	if m.conflict != nil {
+		var changes strings.Builder
+		for _, ch := range m.conflict.Changes {
+			changes.WriteString(m.T("• %s\n    mine:   %s\n    theirs: %s\n", ch.Path, m.orUnset(ch.Mine), m.orUnset(ch.Theirs)))
+		}
+		return m.CenterView(
+			st.DialogBox.Render(
+				st.AppTitle.Render(m.T("Config Changed On Disk")) + "\n\n" +
+					st.Description.Render(
+						m.T("%s was edited outside Mainframe\n"+
+							"while these settings had unsaved changes:", m.config.File())+"\n\n"+
+							changes.String(),
+					) + "\n" +
+					st.PageFooter.Render(m.ShortHelp(m.Keys().Merge, m.Keys().Mine, m.Keys().Theirs)),
+			),
+		)
+	}
+
+	if m.presence.broadcast != nil {
+		return m.broadcastView()
+	}
+	if m.palette.open {
+		return m.paletteView()
+	}
+
+	start := m.Now()
+	view := m.screen.View()
+	m.Metrics().Frame(m.Now().Sub(start))
+	if m.errorMsg != "" {
+		view += "\n" + st.ErrorText.Render("config: "+m.errorMsg)
+	}
+	return view
+}
+
+func (m *BaseModel) orUnset(v string) string {
+	if v == "" {
+		return m.T("(unset)")
+	}
+	return v
+}
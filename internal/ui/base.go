@@ -1,8 +1,15 @@
 package ui
 
 import (
+	"mainframe/pkg/i18n"
+	"mainframe/pkg/keys"
+	"mainframe/pkg/metrics"
 	"mainframe/pkg/styles"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -13,42 +20,224 @@ type WindowSizeMsg struct {
 	Height int
 }
 
+// screen is implemented by every model that embeds BaseModel
+type screen interface {
+	tea.Model
+	base() *BaseModel
+}
+
 // BaseModel provides common functionality for all models
 type BaseModel struct {
-	width  int
-	height int
+	width   int
+	height  int
+	styles  *styles.Styles
+	metrics *metrics.Recorder
+	clock   func() time.Time
+	keys    *keys.KeyMap
+	locale  *i18n.Locale
+	zones   *zones
+	content contentPanel
 }
 
 func (m *BaseModel) Init() tea.Cmd {
 	return nil
 }
 
+func (m *BaseModel) base() *BaseModel {
+	return m
+}
+
+// Styles returns the session's styles, falling back to styles for the
+// default renderer when the model is used on its own
+func (m *BaseModel) Styles() *styles.Styles {
+	if m.styles == nil {
+		m.styles = styles.New(lipgloss.DefaultRenderer())
+	}
+	return m.styles
+}
+
+// UseStyles makes the model render with the given session styles
+func (m *BaseModel) UseStyles(s *styles.Styles) {
+	m.styles = s
+}
+
+// Keys returns the session's key bindings, falling back to the default
+// preset when the model is used on its own
+func (m *BaseModel) Keys() *keys.KeyMap {
+	if m.keys == nil {
+		m.keys = keys.Default()
+	}
+	return m.keys
+}
+
+// Locale returns the session's language, falling back to English when
+// the model is used on its own
+func (m *BaseModel) Locale() *i18n.Locale {
+	if m.locale == nil {
+		m.locale = i18n.New("en")
+	}
+	return m.locale
+}
+
+// T translates format into the session's language and formats it with
+// args as fmt.Sprintf does
+func (m *BaseModel) T(format string, args ...any) string {
+	return m.Locale().Sprintf(format, args...)
+}
+
+// ShortHelp renders bindings as a one line footer
+func (m *BaseModel) ShortHelp(bindings ...key.Binding) string {
+	return m.help().ShortHelpView(m.translate(bindings))
+}
+
+// FullHelp renders groups of bindings as columns
+func (m *BaseModel) FullHelp(groups ...[]key.Binding) string {
+	translated := make([][]key.Binding, len(groups))
+	for i, g := range groups {
+		translated[i] = m.translate(g)
+	}
+	return m.help().FullHelpView(translated)
+}
+
+// translate returns copies of bindings with their help in the session's
+// language. Linear output spells the icons out here, so the help is cut
+// to the width it is shown at.
+func (m *BaseModel) translate(bindings []key.Binding) []key.Binding {
+	st := m.Styles()
+	out := make([]key.Binding, len(bindings))
+	for i, b := range bindings {
+		out[i] = keys.Describe(b, st.Plain(m.T(b.Help().Desc)))
+		out[i].SetHelp(st.Plain(b.Help().Key), out[i].Help().Desc)
+	}
+	return out
+}
+
+func (m *BaseModel) help() help.Model {
+	st := m.Styles()
+	h := help.New()
+	h.Width = st.DialogWidth()
+	h.Ellipsis = st.Plain(h.Ellipsis)
+	h.ShortSeparator = st.Plain(h.ShortSeparator)
+	h.Styles = help.Styles{
+		Ellipsis:       st.HelpDesc,
+		ShortKey:       st.HelpKey,
+		ShortDesc:      st.HelpDesc,
+		ShortSeparator: st.HelpDesc,
+		FullKey:        st.HelpKey,
+		FullDesc:       st.HelpDesc,
+		FullSeparator:  st.HelpDesc,
+	}
+	return h
+}
+
+// Metrics returns the session's timing recorder, falling back to a
+// recorder of its own when the model is used on its own
+func (m *BaseModel) Metrics() *metrics.Recorder {
+	if m.metrics == nil {
+		m.metrics = metrics.NewRecorder()
+	}
+	return m.metrics
+}
+
+// Now returns the session's current time, which replays pin to the time
+// of the message being replayed
+func (m *BaseModel) Now() time.Time {
+	if m.clock == nil {
+		return time.Now()
+	}
+	return m.clock()
+}
+
 func (m *BaseModel) UpdateSize(width, height int) {
 	m.width = width
 	m.height = height
-	styles.UpdateSplitSizes(width, height)
+	m.Styles().Resize(width, height)
 }
 
-// SplitView renders content in a split view layout
+// SplitView renders content in a split view layout. The panes are the
+// zones zoneMenu and zoneContent.
 func (m *BaseModel) SplitView(left, right string) string {
-	return styles.DocStyle.Render(
+	st := m.Styles()
+	if st.Accessible() {
+		// One pane after the other, so the menu is read before the details
+		return clipLines(m.Mark(zoneMenu, left)+"\n\n"+m.Mark(zoneContent, right), m.height)
+	}
+	// A menu too long for the pane loses its last lines rather than
+	// pushing the frame past the bottom of the terminal, where the top
+	// would scroll out of sight
+	left = clipLines(left, st.SplitLeft.GetHeight()-st.SplitLeft.GetVerticalPadding())
+	right = clipLines(right, st.SplitRight.GetHeight()-st.SplitRight.GetVerticalPadding())
+	return st.DocStyle.Render(
 		lipgloss.JoinHorizontal(
 			lipgloss.Top,
-			styles.SplitLeft.Render(left),
-			styles.SplitRight.Render(right),
+			m.Mark(zoneMenu, st.SplitLeft.Render(left)),
+			m.Mark(zoneContent, st.SplitRight.Render(right)),
 		),
 	)
 }
 
+// MenuOption renders the i-th option of a menu, highlighted when the
+// cursor is on it. Long options wrap under their first line inside the
+// menu box, so the box does not wrap them again and they can be clicked.
+func (m *BaseModel) MenuOption(i, cursor int, option string) string {
+	st := m.Styles()
+	style, prefix := st.MenuOption, "  "
+	if i == cursor {
+		style, prefix = st.HighlightedOption, "> "
+		if st.Accessible() {
+			option += " " + m.T("(selected)")
+		}
+	}
+	if w := st.MenuBox.GetWidth() - st.MenuBox.GetHorizontalPadding(); w > 0 {
+		// The box pads the right side already. Copy, as setting a rule
+		// changes every copy of a style.
+		style = style.Copy().Width(w).PaddingRight(0)
+		if text := w - style.GetHorizontalPadding() - len(prefix); text > 0 {
+			option = st.Renderer().NewStyle().Width(text).Render(option)
+		}
+	}
+	lines := strings.Split(option, "\n")
+	for j := range lines {
+		lines[j] = prefix + lines[j]
+		prefix = "  "
+	}
+	return m.Mark(optionZone(i), style.Render(strings.Join(lines, "\n")))
+}
+
+// MoveCursor moves a menu cursor over n options by delta, keeping it on
+// the menu. Moving to another option scrolls its content back to the top.
+func (m *BaseModel) MoveCursor(cursor *int, delta, n int) {
+	next := max(0, min(*cursor+delta, n-1))
+	if next != *cursor {
+		*cursor = next
+		m.content.GotoTop()
+	}
+}
+
 // CenterView renders content in a centered layout
 func (m *BaseModel) CenterView(content string) string {
-	return styles.DocStyle.Render(
-		lipgloss.Place(
-			m.width,
-			m.height,
+	st := m.Styles()
+	if st.Accessible() {
+		return content
+	}
+	width, height := st.DocStyle.GetFrameSize()
+	width, height = max(m.width-width, 0), max(m.height-height, 0)
+	return st.DocStyle.Render(
+		st.Renderer().Place(
+			width,
+			height,
 			lipgloss.Center,
 			lipgloss.Center,
-			content,
+			clipLines(content, height),
 		),
 	)
 }
+
+// clipLines cuts s down to its first n lines
+func clipLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if n <= 0 || len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[:n], "\n")
+}
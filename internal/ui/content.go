@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// contentPanel scrolls the detail text of the split view screens. Tab
+// moves the focus between the menu and the panel; while the panel has it,
+// up and down scroll instead of moving the menu cursor.
+type contentPanel struct {
+	viewport.Model
+	focused bool
+}
+
+func (c *contentPanel) scroll(lines int) {
+	if lines < 0 {
+		c.LineUp(-lines)
+	} else {
+		c.LineDown(lines)
+	}
+}
+
+// ContentKey handles the keys of the content panel and reports whether it
+// used msg. Screens call it before their own key handling.
+func (m *BaseModel) ContentKey(msg tea.KeyMsg) bool {
+	k := m.Keys()
+	c := &m.content
+	switch {
+	case key.Matches(msg, k.Focus):
+		c.focused = !c.focused
+	case key.Matches(msg, k.PageUp):
+		c.ViewUp()
+	case key.Matches(msg, k.PageDown):
+		c.ViewDown()
+	case !c.focused:
+		return false
+	case key.Matches(msg, k.Up):
+		c.LineUp(1)
+	case key.Matches(msg, k.Down):
+		c.LineDown(1)
+	case key.Matches(msg, k.Top):
+		c.GotoTop()
+	case key.Matches(msg, k.Bottom):
+		c.GotoBottom()
+	default:
+		return false
+	}
+	return true
+}
+
+// ContentView renders content in the content box, as much of it as fits in
+// the right pane. Content that does not fit scrolls, with its position
+// shown at the bottom of the box.
+func (m *BaseModel) ContentView(content string) string {
+	st := m.Styles()
+	box := st.ContentBox
+	if m.content.focused {
+		box = box.Copy().BorderStyle(lipgloss.ThickBorder())
+	}
+	width := box.GetWidth() - box.GetHorizontalPadding()
+	// Screen readers page through the text themselves
+	if width <= 0 || m.height == 0 || st.Accessible() {
+		return box.Render(content)
+	}
+
+	// The pane and the box each have a border and padding above and below
+	height := max(st.SplitRight.GetHeight()-st.SplitRight.GetVerticalPadding()-box.GetVerticalFrameSize(), 2)
+	wrapped := st.Renderer().NewStyle().Width(width).Render(content)
+	lines := lipgloss.Height(wrapped)
+
+	c := &m.content
+	c.Width = width
+	c.Height = min(lines, height)
+	if lines <= height {
+		c.SetContent(wrapped)
+		c.GotoTop()
+		return box.Render(c.View())
+	}
+
+	// Leave a line for the position
+	c.Height = height - 1
+	c.SetContent(wrapped)
+	c.SetYOffset(c.YOffset)
+	position := m.T("lines %d-%d of %d • %d%%",
+		c.YOffset+1, c.YOffset+c.VisibleLineCount(), lines, int(c.ScrollPercent()*100+0.5))
+	return box.Render(c.View() + "\n" +
+		st.HelpDesc.Copy().Width(width).Align(lipgloss.Right).Render(position))
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"fmt"
+	"mainframe/pkg/config"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// debugHistory is how many messages and errors the debug overlay keeps
+const debugHistory = 12
+
+// debugState is what the app remembers for the debug overlay
+type debugState struct {
+	visible bool
+	started map[string]string
+	msgs    []debugRecord
+	errors  []debugRecord
+}
+
+type debugRecord struct {
+	at       time.Time
+	text     string
+	duration time.Duration
+}
+
+// newDebugState snapshots the config so the overlay can show what
+// changed since startup
+func newDebugState(cfg *config.Config) debugState {
+	return debugState{started: configValues(cfg)}
+}
+
+func configValues(cfg *config.Config) map[string]string {
+	values := map[string]string{}
+	for _, f := range config.Fields() {
+		v, _ := cfg.Get(f.Key)
+		if f.Secret && v != "" {
+			v = "********"
+		}
+		values[f.Key] = v
+	}
+	return values
+}
+
+func (d *debugState) record(msg tea.Msg, at time.Time, took time.Duration) {
+	d.msgs = appendRecord(d.msgs, debugRecord{at, describeMsg(msg), took})
+}
+
+func (d *debugState) recordError(at time.Time, err error) {
+	d.errors = appendRecord(d.errors, debugRecord{at: at, text: err.Error()})
+}
+
+func appendRecord(records []debugRecord, r debugRecord) []debugRecord {
+	records = append(records, r)
+	if len(records) > debugHistory {
+		records = records[len(records)-debugHistory:]
+	}
+	return records
+}
+
+// describeMsg names a message by type with a short rendering of its value
+func describeMsg(msg tea.Msg) string {
+	var value string
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		value = msg.String()
+	case tea.WindowSizeMsg:
+		value = fmt.Sprintf("%dx%d", msg.Width, msg.Height)
+	case tea.MouseMsg:
+		value = fmt.Sprintf("%s at %d,%d", tea.MouseEvent(msg), msg.X, msg.Y)
+	default:
+		value = fmt.Sprintf("%+v", msg)
+	}
+	return fmt.Sprintf("%T %s", msg, value)
+}
+
+// debugView renders the overlay contents
+func (m *AppModel) debugView(width int) string {
+	st := m.Styles()
+	d := m.debug
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Screen: %T\nWindow: %dx%d\n", m.screen, m.width, m.height)
+
+	b.WriteString("\n" + st.SectionTitle.Render(m.T("Messages")) + "\n")
+	for i := len(d.msgs) - 1; i >= 0; i-- {
+		r := d.msgs[i]
+		fmt.Fprintf(&b, "%s %8s %s\n", r.at.Format("15:04:05.000"), formatDuration(r.duration), r.text)
+	}
+
+	b.WriteString("\n" + st.SectionTitle.Render(m.T("Config Since Startup")) + "\n")
+	changed := false
+	now := configValues(m.config)
+	for _, f := range config.Fields() {
+		if before := d.started[f.Key]; before != now[f.Key] {
+			fmt.Fprintf(&b, "%s: %s → %s\n", f.Key, m.orUnset(before), m.orUnset(now[f.Key]))
+			changed = true
+		}
+	}
+	if !changed {
+		b.WriteString(m.T("no changes") + "\n")
+	}
+
+	b.WriteString("\n" + st.SectionTitle.Render(m.T("Recent Errors")) + "\n")
+	for i := len(d.errors) - 1; i >= 0; i-- {
+		r := d.errors[i]
+		b.WriteString(st.ErrorText.Render(r.at.Format("15:04:05")+" "+r.text) + "\n")
+	}
+	if len(d.errors) == 0 {
+		b.WriteString(m.T("none") + "\n")
+	}
+
+	// Cut every line to fit so the box keeps its shape
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = truncate.StringWithTail(line, uint(width), "…")
+	}
+	return st.DialogBox.Copy().Margin(0).Render(
+		st.WarningText.Render("DEBUG") + " " + m.T("%s to hide", m.Keys().Debug.Help().Key) + "\n\n" + strings.Join(lines, "\n"),
+	)
+}
+
+// overlay draws box over the top right corner of view
+func overlay(view, box string, width int) string {
+	lines := strings.Split(view, "\n")
+	boxLines := strings.Split(box, "\n")
+	left := max(width-lipgloss.Width(box), 0)
+
+	for i, bl := range boxLines {
+		if i >= len(lines) {
+			lines = append(lines, "")
+		}
+		base := truncate.String(lines[i], uint(left))
+		// Stop colors of the cut line from running into the box
+		if strings.Contains(base, "\x1b[") {
+			base += "\x1b[0m"
+		}
+		if pad := left - lipgloss.Width(base); pad > 0 {
+			base += strings.Repeat(" ", pad)
+		}
+		lines[i] = base + bl
+	}
+	return strings.Join(lines, "\n")
+}
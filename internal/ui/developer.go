@@ -1,20 +1,28 @@
 package ui
 
 import (
+	"fmt"
 	"mainframe/pkg/config"
-	"mainframe/pkg/styles"
+	"mainframe/pkg/keys"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type DeveloperModel struct {
 	BaseModel
-	choices     []string
-	cursor      int
-	config      *config.Config
-	quit        bool
-	showHelp    bool
-	description string
+	choices  []string
+	cursor   int
+	config   *config.Config
+	quit     bool
+	showHelp bool
+	errorMsg string
+	// description is the English text of the description, translated
+	// with descriptionArgs when shown
+	description     string
+	descriptionArgs []any
 }
 
 func NewDeveloperModel(cfg *config.Config) *DeveloperModel {
@@ -22,9 +30,10 @@ func NewDeveloperModel(cfg *config.Config) *DeveloperModel {
 		choices: []string{
 			"Debug Mode",
 			"Log Output",
-			"Experimental Features",
+			"Feature Flags",
 			"Performance Metrics",
 			"Network Diagnostics",
+			"View Logs",
 			"Back to Settings",
 		},
 		cursor:      0,
@@ -44,58 +53,133 @@ func (m *DeveloperModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		k := m.Keys()
+		if !m.showHelp && m.ContentKey(msg) {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, k.Quit):
 			m.quit = true
 			return m, tea.Quit
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
+		case key.Matches(msg, k.Up):
+			m.MoveCursor(&m.cursor, -1, len(m.choices))
 			m.updateDescription()
-		case "down", "j":
-			if m.cursor < len(m.choices)-1 {
-				m.cursor++
-			}
+		case key.Matches(msg, k.Down):
+			m.MoveCursor(&m.cursor, 1, len(m.choices))
 			m.updateDescription()
-		case "enter", " ":
-			switch m.cursor {
-			case 0: // Debug Mode
-				m.config.Debug = !m.config.Debug
-			case 1: // Log Output
-				m.config.Logs = !m.config.Logs
-			case 2: // Experimental Features
-				m.config.Experimental = !m.config.Experimental
-			case 5: // Back to Settings
-				return NewSettingsModel(), nil
-			}
-			config.Save(m.config)
-		case "?":
+		case key.Matches(msg, k.Select):
+			return m.choose()
+		case key.Matches(msg, k.Help):
 			m.showHelp = !m.showHelp
-		case "esc":
-			return NewSettingsModel(), nil
+		case key.Matches(msg, k.Back):
+			return NewSettingsModel(m.config), nil
+		}
+
+	case tea.MouseMsg:
+		if m.showHelp {
+			return m, nil
 		}
+		if msg.Type == tea.MouseLeft {
+			if toggle, ok := m.dashboardToggle(msg); ok {
+				m.toggle(toggle)
+				return m, nil
+			}
+		}
+		clicked := m.MenuMouse(msg, &m.cursor, len(m.choices))
+		m.updateDescription()
+		if clicked {
+			return m.choose()
+		}
+	}
+	return m, nil
+}
+
+// choose toggles or opens the choice under the cursor
+func (m *DeveloperModel) choose() (tea.Model, tea.Cmd) {
+	switch m.cursor {
+	case 0: // Debug Mode
+		m.toggle(&m.config.Profile().Debug)
+	case 1: // Log Output
+		m.toggle(&m.config.Profile().Logs)
+	case 2: // Feature Flags
+		return NewFeaturesModel(m.config), nil
+	case 3: // Performance Metrics
+		return NewPerformanceModel(m.config), nil
+	case 4: // Network Diagnostics
+		return NewNetworkModel(m.config), nil
+	case 5: // View Logs
+		return NewLogViewerModel(m.config), nil
+	case 6: // Back to Settings
+		return NewSettingsModel(m.config), nil
 	}
 	return m, nil
 }
 
+// toggle flips a setting and saves it, flipping it back if the config
+// cannot be written
+func (m *DeveloperModel) toggle(setting *bool) {
+	*setting = !*setting
+	m.errorMsg = ""
+	if err := config.Save(m.config); err != nil {
+		*setting = !*setting
+		m.errorMsg = m.T("Error saving config: %v", err)
+	}
+}
+
+// Zones of the status dashboard lines that toggle a setting when clicked
+const (
+	zoneDebugToggle        = "toggle:debug"
+	zoneLogsToggle         = "toggle:logs"
+	zoneExperimentalToggle = "toggle:experimental"
+)
+
+// dashboardToggle returns the setting whose dashboard line was clicked
+func (m *DeveloperModel) dashboardToggle(msg tea.MouseMsg) (*bool, bool) {
+	p := m.config.Profile()
+	switch {
+	case m.InZone(zoneDebugToggle, msg):
+		return &p.Debug, true
+	case m.InZone(zoneLogsToggle, msg):
+		return &p.Logs, true
+	case m.InZone(zoneExperimentalToggle, msg):
+		return &p.Experimental, true
+	}
+	return nil, false
+}
+
 func (m *DeveloperModel) updateDescription() {
+	m.descriptionArgs = nil
 	switch m.cursor {
 	case 0:
-		m.description = "Enable detailed debug information and error reporting for troubleshooting"
+		m.description = "Enable detailed debug information and error reporting for troubleshooting. Press %s on any screen to show the debug overlay"
+		m.descriptionArgs = []any{m.Keys().Debug.Help().Key}
 	case 1:
-		m.description = "Save detailed logs to ~/.mainframe/logs for system analysis"
+		m.description = "Save detailed logs to %s for system analysis"
+		m.descriptionArgs = []any{m.config.LogsDir()}
 	case 2:
-		m.description = "Enable experimental features and updates (may be unstable)"
+		m.description = "Turn individual experimental features on or off, or all of them at once (may be unstable)"
 	case 3:
 		m.description = "Monitor system performance, memory usage, and resource allocation"
 	case 4:
 		m.description = "Test network connectivity and API endpoint responsiveness"
 	case 5:
+		m.description = "Read, search and follow the logs in %s"
+		m.descriptionArgs = []any{m.config.LogsDir()}
+	case 6:
 		m.description = "Return to the settings menu"
 	}
 }
 
+// featuresOn counts the feature flags enabled for the active profile
+func (m *DeveloperModel) featuresOn() (on, total int) {
+	for _, f := range config.Features() {
+		if m.config.Enabled(f.Name, m.Now()) {
+			on++
+		}
+	}
+	return on, len(config.Features())
+}
+
 func getStatusIcon(enabled bool) string {
 	if enabled {
 		return "◉" // Filled circle for enabled
@@ -103,18 +187,31 @@ func getStatusIcon(enabled bool) string {
 	return "○" // Empty circle for disabled
 }
 
+// toggleOption labels a menu option that turns something on or off, in
+// words when output is linear
+func (m *BaseModel) toggleOption(name string, on bool, onStyle lipgloss.Style) string {
+	switch {
+	case m.Styles().Accessible():
+		return name + ": " + m.T(onOffText(on))
+	case on:
+		return getStatusIcon(on) + " " + name + " " + onStyle.Render(m.T("ON"))
+	}
+	return getStatusIcon(on) + " " + name + " " + m.T("OFF")
+}
+
 func (m *DeveloperModel) View() string {
+	st := m.Styles()
+	k := m.Keys()
 	if m.showHelp {
 		return m.CenterView(
-			styles.DialogBox.Render(
-				styles.AppTitle.Render("Developer Options Help") + "\n\n" +
-					"Navigation:\n" +
-					"• Up/Down or j/k: Move cursor\n" +
-					"• Enter/Space: Toggle option\n" +
-					"• ?: Toggle help\n" +
-					"• Esc: Back to settings\n" +
-					"• Ctrl+c/q: Quit\n\n" +
-					styles.PageFooter.Render("Press ? to close help"),
+			st.DialogBox.Render(
+				st.AppTitle.Render(m.T("Developer Options Help")) + "\n\n" +
+					m.FullHelp(
+						[]key.Binding{k.Up, k.Down, keys.Describe(k.Select, "toggle or open"), k.Palette},
+						[]key.Binding{keys.Describe(k.Debug, "debug overlay (with Debug Mode on)"), k.Help, keys.Describe(k.Back, "back to settings"), k.Quit},
+						[]key.Binding{k.Focus, k.PageUp, k.PageDown},
+					) + "\n\n" +
+					st.PageFooter.Render(m.ShortHelp(keys.Describe(k.Help, "close help"))),
 			),
 		)
 	}
@@ -122,92 +219,93 @@ func (m *DeveloperModel) View() string {
 	// Left panel - Menu options
 	var menuContent string
 	for i, choice := range m.choices {
-		cursor := "  "
-		if m.cursor == i {
-			cursor = "> "
-		}
-
-		status := ""
-		icon := ""
+		choice = m.T(choice)
 		switch i {
 		case 0:
-			icon = getStatusIcon(m.config.Debug)
-			if m.config.Debug {
-				status = " " + styles.SuccessText.Render("ON")
-			} else {
-				status = " OFF"
-			}
+			choice = m.toggleOption(choice, m.config.Profile().Debug, st.SuccessText)
 		case 1:
-			icon = getStatusIcon(m.config.Logs)
-			if m.config.Logs {
-				status = " " + styles.SuccessText.Render("ON")
-			} else {
-				status = " OFF"
-			}
+			choice = m.toggleOption(choice, m.config.Profile().Logs, st.SuccessText)
 		case 2:
-			icon = getStatusIcon(m.config.Experimental)
-			if m.config.Experimental {
-				status = " " + styles.WarningText.Render("ON")
+			on, total := m.featuresOn()
+			if st.Accessible() {
+				choice = choice + ": " + m.T("%d of %d on", on, total)
 			} else {
-				status = " OFF"
+				choice = fmt.Sprintf("%s %s %d/%d", getStatusIcon(on > 0), choice, on, total)
 			}
 		case 3, 4:
-			icon = "⊘" // Disabled icon
-			status = " " + styles.ErrorText.Render("SOON")
+			if !st.Accessible() {
+				choice = "◈ " + choice
+			}
+		default:
+			if !st.Accessible() {
+				choice = " " + choice
+			}
 		}
 
-		option := icon + " " + choice + status
-		if m.cursor == i {
-			menuContent += styles.HighlightedOption.Render(cursor+option) + "\n"
-		} else {
-			menuContent += styles.MenuOption.Render(cursor+option) + "\n"
-		}
+		menuContent += m.MenuOption(i, m.cursor, choice) + "\n"
 	}
 
-	menuView := styles.MenuBox.Render(
-		styles.SectionTitle.Render("Developer Options") + "\n\n" +
-			menuContent + "\n\n" +
-			styles.PageFooter.Render("? for help • esc to go back"),
+	menuView := st.MenuBox.Render(
+		st.SectionTitle.Render(m.T("Developer Options")) + "\n\n" +
+			menuContent + "\n" +
+			st.MenuFooter.Render(m.ShortHelp(k.Help, k.Back)),
 	)
 
 	// Right panel - Detailed content
-	detailContent := styles.MainTitle.Render("Developer Tools") + "\n\n"
+	detailContent := st.MainTitle.Render(m.T("Developer Tools")) + "\n\n"
 
 	// Add feature description
-	detailContent += styles.Description.Render(m.description) + "\n\n"
+	detailContent += st.Description.Render(m.T(m.description, m.descriptionArgs...)) + "\n\n"
 
 	// Add system information
-	detailContent += styles.SectionTitle.Render("System Information") + "\n" +
-		styles.Description.Render(
-			"• Config Path: ~/.mainframe/config.json\n"+
-				"• Log Path: ~/.mainframe/logs\n"+
-				"• Debug Level: "+(func() string {
-				if m.config.Debug {
-					return styles.SuccessText.Render("VERBOSE")
-				}
-				return "NORMAL"
-			})(),
+	level := m.T("NORMAL")
+	if m.config.Profile().Debug {
+		level = st.SuccessText.Render(m.T("VERBOSE"))
+	}
+	detailContent += st.SectionTitle.Render(m.T("System Information")) + "\n" +
+		st.Description.Render(
+			"• "+m.T("Config Path: %s", m.config.File())+"\n"+
+				"• "+m.T("Log Path: %s", m.config.LogsDir())+"\n"+
+				"• "+m.T("Debug Level: %s", level),
 		) + "\n\n"
 
-	// Add status dashboard
-	detailContent += styles.SectionTitle.Render("Status Dashboard") + "\n" +
-		styles.Description.Render(
-			"Debug Mode:          "+getStatusIndicator(m.config.Debug)+"\n"+
-				"Log Output:          "+getStatusIndicator(m.config.Logs)+"\n"+
-				"Experimental Mode:   "+getStatusIndicator(m.config.Experimental)+"\n"+
-				"Performance Monitor: "+styles.ErrorText.Render("NOT AVAILABLE")+"\n"+
-				"Network Diagnostics: "+styles.ErrorText.Render("NOT AVAILABLE"),
+	// Add status dashboard, where the toggles can be clicked. The labels
+	// line up whatever their length in the current language.
+	on, total := m.featuresOn()
+	labels := []string{
+		m.T("Debug Mode"), m.T("Log Output"), m.T("Experimental Mode"),
+		m.T("Feature Flags"), m.T("Performance Monitor"), m.T("Network Diagnostics"),
+	}
+	width := 0
+	for _, l := range labels {
+		width = max(width, lipgloss.Width(l))
+	}
+	row := func(i int, value string) string {
+		return labels[i] + ":" + strings.Repeat(" ", width-lipgloss.Width(labels[i])+1) + value
+	}
+	detailContent += st.SectionTitle.Render(m.T("Status Dashboard")) + "\n" +
+		st.Description.Render(
+			m.Mark(zoneDebugToggle, row(0, m.statusIndicator(m.config.Profile().Debug)))+"\n"+
+				m.Mark(zoneLogsToggle, row(1, m.statusIndicator(m.config.Profile().Logs)))+"\n"+
+				m.Mark(zoneExperimentalToggle, row(2, m.statusIndicator(m.config.Profile().Experimental)))+"\n"+
+				row(3, m.T("%d of %d on", on, total))+"\n"+
+				row(4, st.SuccessText.Render(m.T("AVAILABLE")))+"\n"+
+				row(5, st.SuccessText.Render(m.T("AVAILABLE"))),
 		)
+	if m.errorMsg != "" {
+		detailContent += "\n\n" + st.ErrorText.Render(m.errorMsg)
+	}
 
-	detailView := styles.ContentBox.Render(detailContent)
+	detailView := m.ContentView(detailContent)
 
 	// Combine views
 	return m.SplitView(menuView, detailView)
 }
 
-func getStatusIndicator(enabled bool) string {
+func (m *BaseModel) statusIndicator(enabled bool) string {
+	st := m.Styles()
 	if enabled {
-		return styles.SuccessText.Render("[ACTIVE]")
+		return st.SuccessText.Render(m.T("[ACTIVE]"))
 	}
-	return styles.WarningText.Render("[INACTIVE]")
+	return st.WarningText.Render(m.T("[INACTIVE]"))
 }
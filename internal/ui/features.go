@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"mainframe/pkg/config"
+	"mainframe/pkg/keys"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// FeaturesModel lists the feature flags with a toggle for each, plus the
+// profile's experimental setting that turns them all on
+type FeaturesModel struct {
+	BaseModel
+	config   *config.Config
+	cursor   int
+	errorMsg string
+}
+
+func NewFeaturesModel(cfg *config.Config) *FeaturesModel {
+	return &FeaturesModel{config: cfg}
+}
+
+func (m *FeaturesModel) Init() tea.Cmd {
+	return nil
+}
+
+// Rows are "all experimental", one per flag, then "back"
+func (m *FeaturesModel) rows() int {
+	return len(config.Features()) + 2
+}
+
+// feature returns the flag under the cursor, if it is on one
+func (m *FeaturesModel) feature() (config.Feature, bool) {
+	i := m.cursor - 1
+	if i < 0 || i >= len(config.Features()) {
+		return config.Feature{}, false
+	}
+	return config.Features()[i], true
+}
+
+func (m *FeaturesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case WindowSizeMsg:
+		m.UpdateSize(msg.Width, msg.Height)
+		return m, nil
+
+	case tea.KeyMsg:
+		k := m.Keys()
+		if m.ContentKey(msg) {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, k.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, k.Up):
+			m.MoveCursor(&m.cursor, -1, m.rows())
+		case key.Matches(msg, k.Down):
+			m.MoveCursor(&m.cursor, 1, m.rows())
+		case key.Matches(msg, k.Select):
+			return m.toggle()
+		case key.Matches(msg, k.ResetFlag):
+			if f, ok := m.feature(); ok {
+				m.config.ResetFeature(f.Name)
+				m.save()
+			}
+		case key.Matches(msg, k.Back):
+			return NewDeveloperModel(m.config), nil
+		}
+
+	case tea.MouseMsg:
+		if m.MenuMouse(msg, &m.cursor, m.rows()) {
+			return m.toggle()
+		}
+	}
+	return m, nil
+}
+
+// toggle flips the row under the cursor, or goes back from the last row
+func (m *FeaturesModel) toggle() (tea.Model, tea.Cmd) {
+	if m.cursor == 0 {
+		p := m.config.Profile()
+		p.Experimental = !p.Experimental
+	} else if f, ok := m.feature(); ok {
+		if f.Expired(m.Now()) {
+			m.errorMsg = m.T("%s has expired and always follows its default", f.Name)
+			return m, nil
+		}
+		if err := m.config.SetFeature(f.Name, !m.config.Enabled(f.Name, m.Now())); err != nil {
+			m.errorMsg = err.Error()
+			return m, nil
+		}
+	} else {
+		return NewDeveloperModel(m.config), nil
+	}
+	m.save()
+	return m, nil
+}
+
+func (m *FeaturesModel) save() {
+	m.errorMsg = ""
+	if err := config.Save(m.config); err != nil {
+		m.errorMsg = m.T("Error saving config: %v", err)
+	}
+}
+
+// source explains why a flag has its current state
+func (m *FeaturesModel) source(f config.Feature) string {
+	p := m.config.Profile()
+	_, set := p.Features[f.Name]
+	switch {
+	case f.Expired(m.Now()):
+		return m.T("expired, following its default")
+	case set:
+		return m.T("set in profile %s", m.config.ActiveProfile)
+	case p.Experimental && f.Stability != config.StabilityStable:
+		return m.T("on with all experimental flags")
+	}
+	return m.T("default")
+}
+
+func (m *FeaturesModel) View() string {
+	st := m.Styles()
+	p := m.config.Profile()
+
+	options := []string{m.toggleOption(m.T("All Experimental Flags"), p.Experimental, st.WarningText)}
+	for _, f := range config.Features() {
+		options = append(options, m.toggleOption(f.Name, m.config.Enabled(f.Name, m.Now()), st.SuccessText))
+	}
+	options = append(options, m.T("Back to Developer Options"))
+
+	var menuContent string
+	for i, option := range options {
+		menuContent += m.MenuOption(i, m.cursor, option) + "\n"
+	}
+
+	menuView := st.MenuBox.Render(
+		st.SectionTitle.Render(m.T("Feature Flags")) + "\n\n" +
+			menuContent + "\n" +
+			st.MenuFooter.Render(m.ShortHelp(keys.Describe(m.Keys().Select, "toggle"), m.Keys().ResetFlag, m.Keys().Back)),
+	)
+
+	detailContent := st.MainTitle.Render(m.T("Feature Flags")) + "\n\n"
+	if f, ok := m.feature(); ok {
+		expires := m.T("never")
+		if !f.Expires.IsZero() {
+			expires = f.Expires.Format("2006-01-02")
+		}
+		detailContent += st.Description.Render(f.Description) + "\n\n" +
+			st.SectionTitle.Render(m.T("Flag")) + "\n" +
+			st.Description.Render(m.T(
+				"Name:      %s\nStability: %s\nDefault:   %s\nExpires:   %s\nState:     %s",
+				f.Name, f.Stability, m.T(onOffText(f.Default)), expires, m.source(f),
+			))
+	} else if m.cursor == 0 {
+		detailContent += st.Description.Render(
+			m.T("Turn on every experimental and beta flag at once. Flags set individually keep their own setting."),
+		)
+	} else {
+		detailContent += st.Description.Render(m.T("Return to the developer options"))
+	}
+	if m.errorMsg != "" {
+		detailContent += "\n\n" + st.ErrorText.Render(m.errorMsg)
+	}
+
+	return m.SplitView(menuView, m.ContentView(detailContent))
+}
+
+func onOffText(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,443 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"mainframe/pkg/config"
+	"mainframe/pkg/model"
+	"mainframe/pkg/styles"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the current output")
+
+// goldenSizes are the terminal sizes every flow is rendered at
+var goldenSizes = [][2]int{{80, 24}, {120, 40}, {160, 50}}
+
+// press turns key names as printed by tea.KeyMsg.String into messages.
+// Anything that is not a named key is typed as runes.
+func press(names ...string) []tea.Msg {
+	named := map[string]tea.KeyType{
+		"up":     tea.KeyUp,
+		"down":   tea.KeyDown,
+		"enter":  tea.KeyEnter,
+		"esc":    tea.KeyEsc,
+		"tab":    tea.KeyTab,
+		"ctrl+n": tea.KeyCtrlN,
+		"ctrl+p": tea.KeyCtrlP,
+		"pgdown": tea.KeyPgDown,
+	}
+	var msgs []tea.Msg
+	for _, n := range names {
+		if t, ok := named[n]; ok {
+			msgs = append(msgs, tea.KeyMsg{Type: t})
+		} else {
+			msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(n)})
+		}
+	}
+	return msgs
+}
+
+// click is replaced by a left click on the middle of a zone of the frame
+// before it
+type click string
+
+// wheel is replaced by a wheel step down over the middle of a zone
+type wheel string
+
+func scroll(zone string, n int) []tea.Msg {
+	msgs := make([]tea.Msg, n)
+	for i := range msgs {
+		msgs[i] = wheel(zone)
+	}
+	return msgs
+}
+
+func steps(parts ...[]tea.Msg) []tea.Msg {
+	var all []tea.Msg
+	for _, p := range parts {
+		all = append(all, p...)
+	}
+	return all
+}
+
+var (
+	toSettings   = press("down", "down", "down", "enter")
+	toDeveloper  = steps(toSettings, press("down", "down", "down", "enter"))
+	toLocalModel = steps(toSettings, press("down", "down", "enter"))
+	toAccessible = press("ctrl+p", "accessible", "enter")
+	setModelPath = steps(toLocalModel, press("down", "enter", "/models/llama.gguf", "enter"))
+	testPassed   = selfTestMsg{report: model.Report{
+		Path:   "/models/llama.gguf",
+		Size:   4 << 30,
+		Format: model.FormatGGUF,
+		Server: config.DefaultModelServer,
+		OK:     true,
+		Steps: []model.Step{
+			{Name: "file", OK: true, Detail: "4.0 GB", Duration: time.Millisecond},
+			{Name: "format", OK: true, Detail: "gguf", Duration: time.Millisecond},
+			{Name: "server", OK: true, Detail: config.DefaultModelServer, Duration: 2 * time.Second},
+			{Name: "inference", OK: true, Detail: "12 tokens", Duration: 800 * time.Millisecond},
+		},
+	}}
+)
+
+// goldenFlows are the scripted sessions compared against testdata
+var goldenFlows = []struct {
+	name string
+	// keys is written to keys.toml before the session starts
+	keys string
+	msgs []tea.Msg
+}{
+	{"home", "", nil},
+	{"settings", "", toSettings},
+	{"settings_emacs", `preset = "emacs"`, press("ctrl+n", "ctrl+n", "ctrl+n", "enter", "ctrl+n")},
+	{"developer", "", toDeveloper},
+	{"developer_help", "", steps(toDeveloper, press("?"))},
+	{"features", "", steps(toDeveloper, press("down", "down", "enter"))},
+	{"localmodel", "", toLocalModel},
+	{"localmodel_path", "", setModelPath},
+	{"localmodel_tested", "", steps(setModelPath, []tea.Msg{testPassed})},
+	{"developer_mouse", "", steps(toDeveloper, []tea.Msg{click(optionZone(1))}, scroll(zoneContent, 6), []tea.Msg{click(zoneDebugToggle)})},
+	{"palette", "", press("ctrl+p")},
+	{"palette_search", "", press("ctrl+p", "dev")},
+	{"palette_jump", "", press("ctrl+p", "flags", "enter")},
+	{"localmodel_pgdown", "", steps(toLocalModel, press("pgdown"))},
+	{"localmodel_focus", "", steps(toLocalModel, press("tab", "down", "down"))},
+	{"home_wheel", "", steps(scroll(optionZone(0), 2), []tea.Msg{click(optionZone(3))})},
+	{"home_accessible", "", toAccessible},
+	{"developer_accessible", "", steps(toAccessible, toDeveloper)},
+	{"settings_spanish", "", steps(toSettings, press("down", "down", "down", "down", "down", "enter"))},
+	{"developer_spanish", "", steps(toSettings, press("down", "down", "down", "down", "down", "enter", "up", "up", "enter"))},
+}
+
+// colorModes are the color settings TestColorGolden renders under
+var colorModes = []struct {
+	name       string
+	color      string
+	background string
+}{
+	{"truecolor", styles.ColorTrueColor, styles.BackgroundDark},
+	{"256", styles.Color256, styles.BackgroundDark},
+	{"16", styles.Color16, styles.BackgroundDark},
+	{"none", styles.ColorNone, styles.BackgroundDark},
+	{"light", styles.ColorTrueColor, styles.BackgroundLight},
+}
+
+// colorFlows reach every screen whose frame does not change from run to
+// run. Performance Metrics is left out as it shows the live process.
+var colorFlows = []struct {
+	name string
+	msgs []tea.Msg
+}{
+	{"home", nil},
+	{"settings", toSettings},
+	{"developer", toDeveloper},
+	{"features", steps(toDeveloper, press("down", "down", "enter"))},
+	{"network", steps(toDeveloper, press("down", "down", "down", "down", "enter"))},
+	{"logs", steps(toDeveloper, press("down", "down", "down", "down", "down", "enter"))},
+	{"localmodel", toLocalModel},
+	{"palette", press("ctrl+p")},
+}
+
+func TestGolden(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", "golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, flow := range goldenFlows {
+		for _, size := range goldenSizes {
+			name := fmt.Sprintf("%s_%dx%d", flow.name, size[0], size[1])
+			t.Run(name, func(t *testing.T) {
+				app := newTestApp(t, flow.keys, nil)
+				view := run(t, app, size, flow.msgs)
+				checkGolden(t, filepath.Join(dir, name+".golden"), size, plain(view))
+			})
+		}
+	}
+}
+
+// TestColorGolden keeps the escape codes, so it catches colors that are
+// wrong or missing under each color mode and background
+func TestColorGolden(t *testing.T) {
+	dir, err := filepath.Abs(filepath.Join("testdata", "golden", "color"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := [2]int{120, 40}
+	for _, flow := range colorFlows {
+		for _, mode := range colorModes {
+			name := flow.name + "_" + mode.name
+			t.Run(name, func(t *testing.T) {
+				app := newTestApp(t, "", map[string]string{"color": mode.color, "background": mode.background})
+				view := run(t, app, size, flow.msgs)
+				checkGolden(t, filepath.Join(dir, name+".golden"), size, view+"\n")
+			})
+		}
+	}
+}
+
+// run plays msgs into app at the given terminal size and returns the
+// final frame
+func run(t *testing.T, app *AppModel, size [2]int, msgs []tea.Msg) string {
+	t.Helper()
+	app.Update(tea.WindowSizeMsg{Width: size[0], Height: size[1]})
+	for _, msg := range msgs {
+		// The program draws a frame after every message, which is where
+		// the mouse zones come from
+		app.View()
+		app.Update(mouse(t, app, msg))
+	}
+	return app.View()
+}
+
+// mouse turns click and wheel into mouse messages at the zone's position
+func mouse(t *testing.T, app *AppModel, msg tea.Msg) tea.Msg {
+	t.Helper()
+	var zone string
+	typ := tea.MouseLeft
+	switch msg := msg.(type) {
+	case click:
+		zone = string(msg)
+	case wheel:
+		zone, typ = string(msg), tea.MouseWheelDown
+	default:
+		return msg
+	}
+	r, ok := app.zones.get(zone)
+	if !ok {
+		t.Fatalf("zone %s is not on screen", zone)
+	}
+	return tea.MouseMsg{X: r.X + r.Width/2, Y: r.Y + r.Height/2, Type: typ}
+}
+
+// newTestApp starts a session in a scratch config directory with fixed
+// clock, language and colors, which settings may override, and the given
+// keys.toml, if any. Commands the app returns are never run, so nothing
+// reaches the network.
+func newTestApp(t *testing.T, keys string, settings map[string]string) *AppModel {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	// Relative, so paths shown on screen are the same on every machine
+	t.Setenv("XDG_CONFIG_HOME", ".")
+	for _, f := range config.Fields() {
+		t.Setenv(f.Env, "")
+		os.Unsetenv(f.Env)
+	}
+	// Network Diagnostics shows the proxy settings
+	for _, env := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy"} {
+		t.Setenv(env, "")
+	}
+	system := config.SystemPath
+	config.SystemPath = filepath.Join(dir, "system.json")
+	t.Cleanup(func() { config.SystemPath = system })
+
+	if keys != "" {
+		if err := os.MkdirAll(config.Dir(), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(config.KeysPath(), []byte(keys), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flags := map[string]string{"color": "truecolor", "background": "dark", "language": "en"}
+	for k, v := range settings {
+		flags[k] = v
+	}
+	cfg, err := config.Load(flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Date(2026, time.January, 5, 9, 30, 0, 0, time.UTC)
+	app := NewAppModel(cfg, AppOptions{
+		Renderer: lipgloss.NewRenderer(io.Discard),
+		Clock:    func() time.Time { return clock },
+	})
+	t.Cleanup(app.Close)
+	app.Init()
+	return app
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// plain strips colors and trailing spaces so golden files stay readable
+func plain(view string) string {
+	lines := strings.Split(ansiEscape.ReplaceAllString(view, ""), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// checkGolden compares got with the golden file at path, after checking
+// that it fits a terminal of the given size
+func checkGolden(t *testing.T, path string, size [2]int, got string) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) > size[1] {
+		t.Errorf("%s is %d lines tall, the terminal %d", filepath.Base(path), len(lines), size[1])
+	}
+	for i, l := range lines {
+		if w := lipgloss.Width(l); w > size[0] {
+			t.Errorf("%s line %d is %d columns wide, the terminal %d", filepath.Base(path), i+1, w, size[0])
+			break
+		}
+	}
+	if t.Failed() {
+		return
+	}
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./internal/ui -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from the rendered view (run go test ./internal/ui -update to accept):\n%s",
+			filepath.Base(path), diffLines(string(want), got))
+	}
+}
+
+// diffLines lists the lines that differ between want and got
+func diffLines(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < max(len(w), len(g)); i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl {
+			fmt.Fprintf(&b, "line %d:\n- %s\n+ %s\n", i+1, wl, gl)
+		}
+	}
+	return b.String()
+}
+
+// TestReplayMatchesSession replays a recording somewhere with no keys.toml,
+// no themes and another locale, and expects the frames of the session
+func TestReplayMatchesSession(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "es_ES.UTF-8")
+	app := newTestApp(t, `preset = "emacs"`, map[string]string{"language": "auto", "color": "256"})
+	if err := os.MkdirAll(app.config.ThemesDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(app.config.ThemesDir(), "amber.toml"), []byte("primary = \"#FFB000\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.config.Set("theme", "amber"); err != nil {
+		t.Fatal(err)
+	}
+
+	var rec strings.Builder
+	session := NewAppModel(app.config, AppOptions{
+		Renderer: lipgloss.NewRenderer(io.Discard),
+		Clock:    app.Now,
+		Record:   &rec,
+	})
+	t.Cleanup(session.Close)
+	session.Init()
+	var want []string
+	for _, msg := range steps([]tea.Msg{tea.WindowSizeMsg{Width: 120, Height: 40}}, press("ctrl+n", "ctrl+n", "ctrl+n", "enter", "ctrl+n")) {
+		session.Update(msg)
+		want = append(want, session.View())
+	}
+
+	if err := os.RemoveAll(app.config.Dir()); err != nil {
+		t.Fatal(err)
+	}
+	// Nothing from the replaying machine may change the frames
+	t.Setenv("LANG", "C")
+	t.Setenv("MAINFRAME_THEME", "default")
+	t.Setenv("MAINFRAME_ACTIVE_PROFILE", "missing")
+	var got []string
+	err := Replay(strings.NewReader(rec.String()), lipgloss.NewRenderer(io.Discard), func(f Frame) error {
+		got = append(got, f.View)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("replayed %d frames, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("frame %d differs:\n%s\nwant:\n%s", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestSaveErrorKeepsSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		msgs    []tea.Msg
+		setting func(*config.Config) string
+	}{
+		{"ai model", steps(toSettings, press("down", "enter")), func(c *config.Config) string { return c.Profile().AIModel }},
+		{"language", steps(toSettings, press("down", "down", "down", "down", "down", "enter")), func(c *config.Config) string { return c.Language }},
+		{"debug mode", steps(toDeveloper, press("enter")), func(c *config.Config) string { return fmt.Sprint(c.Profile().Debug) }},
+		{"model path", setModelPath, func(c *config.Config) string { return c.Profile().ModelPath }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t, "", nil)
+			want := tt.setting(app.config)
+			// Someone else edits the file, so saving is refused
+			if err := os.WriteFile(app.config.File(), []byte(`{"theme": "default"}`), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			view := plain(run(t, app, [2]int{160, 50}, tt.msgs))
+			if got := tt.setting(app.config); got != want {
+				t.Errorf("setting changed to %q though it was not saved, want %q", got, want)
+			}
+			if !strings.Contains(view, "Error saving config") {
+				t.Errorf("save error not shown:\n%s", view)
+			}
+		})
+	}
+}
+
+func TestDescriptionsNameTheBoundKeys(t *testing.T) {
+	app := newTestApp(t, "[bindings]\nselect = [\"ctrl+o\"]\nnew_profile = [\"ctrl+b\"]\n", nil)
+	view := plain(run(t, app, [2]int{160, 50}, press("down", "down", "down", "ctrl+o")))
+	for _, want := range []string{"Press ctrl+o to switch to the next profile", "Press ctrl+b to create a new profile"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("%q not shown:\n%s", want, view)
+		}
+	}
+}
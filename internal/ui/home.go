@@ -1,8 +1,9 @@
 package ui
 
 import (
-	"mainframe/pkg/styles"
+	"mainframe/pkg/config"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -10,10 +11,11 @@ type HomeModel struct {
 	BaseModel
 	choices []string
 	cursor  int
+	config  *config.Config
 	quit    bool
 }
 
-func NewHomeModel() *HomeModel {
+func NewHomeModel(cfg *config.Config) *HomeModel {
 	return &HomeModel{
 		choices: []string{
 			"Start Lesson",
@@ -23,6 +25,7 @@ func NewHomeModel() *HomeModel {
 			"Exit",
 		},
 		cursor: 0,
+		config: cfg,
 		quit:   false,
 	}
 }
@@ -38,66 +41,66 @@ func (m *HomeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		k := m.Keys()
+		switch {
+		case key.Matches(msg, k.Quit):
 			m.quit = true
 			return m, tea.Quit
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case "down", "j":
-			if m.cursor < len(m.choices)-1 {
-				m.cursor++
-			}
-		case "enter", " ":
-			switch m.choices[m.cursor] {
-			case "Exit":
-				m.quit = true
-				return m, tea.Quit
-			case "Start Lesson":
-				// TODO: Implement transition to lesson view
-				return m, nil
-			case "Sandbox Mode":
-				// TODO: Implement transition to sandbox view
-				return m, nil
-			case "Challenges":
-				// TODO: Implement transition to challenges view
-				return m, nil
-			case "Settings":
-				return NewSettingsModel(), nil
-			}
+		case key.Matches(msg, k.Up):
+			m.MoveCursor(&m.cursor, -1, len(m.choices))
+		case key.Matches(msg, k.Down):
+			m.MoveCursor(&m.cursor, 1, len(m.choices))
+		case key.Matches(msg, k.Select):
+			return m.choose()
 		}
+
+	case tea.MouseMsg:
+		if m.MenuMouse(msg, &m.cursor, len(m.choices)) {
+			return m.choose()
+		}
+	}
+	return m, nil
+}
+
+// choose opens the choice under the cursor
+func (m *HomeModel) choose() (tea.Model, tea.Cmd) {
+	switch m.choices[m.cursor] {
+	case "Exit":
+		m.quit = true
+		return m, tea.Quit
+	case "Start Lesson":
+		// TODO: Implement transition to lesson view
+		return m, nil
+	case "Sandbox Mode":
+		// TODO: Implement transition to sandbox view
+		return m, nil
+	case "Challenges":
+		// TODO: Implement transition to challenges view
+		return m, nil
+	case "Settings":
+		return NewSettingsModel(m.config), nil
 	}
 	return m, nil
 }
 
 func (m *HomeModel) View() string {
+	st := m.Styles()
 	var menuContent string
 	for i, choice := range m.choices {
-		cursor := "  "
-		if m.cursor == i {
-			cursor = "> "
-		}
-
-		if m.cursor == i {
-			menuContent += styles.HighlightedOption.Render(cursor+choice) + "\n"
-		} else {
-			menuContent += styles.MenuOption.Render(cursor+choice) + "\n"
-		}
+		menuContent += m.MenuOption(i, m.cursor, m.T(choice)) + "\n"
 	}
 
-	content := styles.AppTitle.Render("Mainframe") + "\n" +
-		styles.SubTitle.Render("An immersive terminal-based learning environment") + "\n\n" +
-		styles.MenuBox.Render(menuContent) + "\n" +
-		styles.Description.Render(
-			"Welcome to Mainframe, your gateway to mastering terminal commands\n"+
+	content := st.AppTitle.Render("Mainframe") + "\n" +
+		st.SubTitle.Render(m.T("An immersive terminal-based learning environment")) + "\n\n" +
+		st.MenuBox.Render(menuContent) + "\n" +
+		st.Description.Render(
+			m.T("Welcome to Mainframe, your gateway to mastering terminal commands\n"+
 				"and system administration through interactive learning.\n\n"+
 				"• Gamified lessons with progressive difficulty\n"+
 				"• Real-world scenarios in a safe environment\n"+
-				"• AI-powered guidance and assistance\n",
+				"• AI-powered guidance and assistance")+"\n",
 		) + "\n" +
-		styles.PageFooter.Render("↑/↓ to move • enter to select • q to quit")
+		st.PageFooter.Render(m.ShortHelp(m.Keys().Up, m.Keys().Down, m.Keys().Select, m.Keys().Palette, m.Keys().Quit))
 
 	return m.CenterView(content)
 }
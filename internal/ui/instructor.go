@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"fmt"
+	"mainframe/pkg/config"
+	"mainframe/pkg/keys"
+	"mainframe/pkg/presence"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// refreshMsg asks the instructor dashboard to re-read heartbeats
+type refreshMsg struct{}
+
+// InstructorModel shows every trainee publishing presence heartbeats and
+// lets the instructor broadcast a message to all of them
+type InstructorModel struct {
+	BaseModel
+	config       *config.Config
+	user         string
+	beats        []presence.Heartbeat
+	cursor       int
+	messageInput textinput.Model
+	showInput    bool
+	lastSent     *presence.Broadcast
+	errorMsg     string
+}
+
+func NewInstructorModel(cfg *config.Config, user string) *InstructorModel {
+	message := textinput.New()
+	message.Placeholder = "Message for every trainee"
+	message.Width = 50
+	message.CharLimit = 280
+
+	if user == "" {
+		user = currentUser()
+	}
+	return &InstructorModel{
+		config:       cfg,
+		user:         user,
+		messageInput: message,
+	}
+}
+
+func (m *InstructorModel) Init() tea.Cmd {
+	m.refresh()
+	return m.tick()
+}
+
+func (m *InstructorModel) tick() tea.Cmd {
+	return tea.Tick(presence.Interval/2, func(time.Time) tea.Msg {
+		return refreshMsg{}
+	})
+}
+
+func (m *InstructorModel) refresh() {
+	if m.config.PresenceDir == "" {
+		m.errorMsg = m.T("presence_dir is not set, so trainees are not publishing heartbeats")
+		return
+	}
+	beats, err := presence.Read(m.config.PresenceDir, m.Now())
+	if err != nil {
+		m.errorMsg = err.Error()
+		return
+	}
+	m.beats = beats
+	if m.cursor >= len(m.beats) {
+		m.cursor = max(len(m.beats)-1, 0)
+	}
+}
+
+func (m *InstructorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case WindowSizeMsg:
+		m.UpdateSize(msg.Width, msg.Height)
+		return m, nil
+
+	case refreshMsg:
+		m.refresh()
+		return m, m.tick()
+
+	case tea.KeyMsg:
+		k := m.Keys()
+		if m.showInput {
+			m.messageInput, cmd = m.messageInput.Update(msg)
+
+			switch {
+			case key.Matches(msg, k.Confirm):
+				text := strings.TrimSpace(m.messageInput.Value())
+				if text == "" {
+					return m, nil
+				}
+				b, err := presence.Send(m.config.PresenceDir, m.user, text, m.config.Instructor)
+				m.errorMsg = ""
+				if err != nil {
+					m.errorMsg = err.Error()
+				}
+				m.lastSent = &b
+				m.showInput = false
+				return m, nil
+			case key.Matches(msg, k.Cancel):
+				m.showInput = false
+				return m, nil
+			}
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, k.Quit, k.Back):
+			return m, tea.Quit
+		case key.Matches(msg, k.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case key.Matches(msg, k.Down):
+			if m.cursor < len(m.beats)-1 {
+				m.cursor++
+			}
+		case key.Matches(msg, k.Refresh):
+			m.refresh()
+		case key.Matches(msg, k.Broadcast):
+			if m.config.PresenceDir == "" {
+				return m, nil
+			}
+			m.showInput = true
+			m.messageInput.SetValue("")
+			m.messageInput.Focus()
+			return m, textinput.Blink
+		}
+	}
+
+	return m, nil
+}
+
+func (m *InstructorModel) View() string {
+	st := m.Styles()
+	if m.showInput {
+		m.messageInput.Placeholder = m.T("Message for every trainee")
+		return m.CenterView(
+			st.DialogBox.Render(
+				st.AppTitle.Render(m.T("Broadcast")) + "\n\n" +
+					st.MenuOption.Render(m.T("Pops up in %d trainee sessions:", len(m.beats))) + "\n" +
+					st.InputBox.Render(m.messageInput.View()) + "\n\n" +
+					st.PageFooter.Render(m.ShortHelp(keys.Describe(m.Keys().Confirm, "send"), m.Keys().Cancel)),
+			),
+		)
+	}
+
+	now := m.Now()
+
+	// Left panel - Trainees
+	var list string
+	for i, hb := range m.beats {
+		cursor := "  "
+		if m.cursor == i {
+			cursor = "> "
+		}
+		line := m.T("%s%-12s %-20s idle %s", cursor, hb.User, hb.Screen, hb.Idle(now))
+		if m.cursor == i {
+			list += st.HighlightedOption.Render(line) + "\n"
+		} else {
+			list += st.MenuOption.Render(line) + "\n"
+		}
+	}
+	if len(m.beats) == 0 {
+		list = st.Description.Render(m.T("Nobody is online")) + "\n"
+	}
+
+	listView := st.MenuBox.Render(
+		st.SectionTitle.Render(m.T("Trainees (%d)", len(m.beats))) + "\n\n" +
+			list + "\n" +
+			st.MenuFooter.Render(m.ShortHelp(m.Keys().Broadcast, m.Keys().Refresh, m.Keys().Quit)),
+	)
+
+	// Right panel - Selected trainee
+	var detail string
+	if len(m.beats) > 0 {
+		hb := m.beats[m.cursor]
+		detail = st.MainTitle.Render(hb.User) + "\n\n" +
+			st.Description.Render(
+				m.T("Screen:   %s\nActivity: %s\nIdle:     %s\nOnline:   %s\nProcess:  %s",
+					hb.Screen, m.orUnset(hb.Activity), hb.Idle(now), now.Sub(hb.Started).Truncate(time.Second),
+					fmt.Sprintf("%s:%d", hb.Host, hb.PID)),
+			)
+	} else {
+		detail = st.MainTitle.Render(m.T("Instructor")) + "\n\n" +
+			st.Description.Render(m.T("Trainees appear here while Mainframe is running\nwith presence_dir set to %s.", m.orUnset(m.config.PresenceDir)))
+	}
+
+	if m.lastSent != nil {
+		detail += "\n\n" + st.StatusIndicator.Render(m.T("Last broadcast at %s: %s", m.lastSent.Sent.Format("15:04"), m.lastSent.Message))
+	}
+	if m.errorMsg != "" {
+		detail += "\n\n" + st.ErrorText.Render(m.errorMsg)
+	}
+
+	return m.SplitView(listView, st.ContentBox.Render(detail))
+}
@@ -1,13 +1,21 @@
 package ui
 
 import (
+	"context"
+	"fmt"
 	"mainframe/pkg/config"
+	"mainframe/pkg/keys"
+	"mainframe/pkg/logging"
+	"mainframe/pkg/model"
 	"mainframe/pkg/styles"
+	"time"
 
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type LocalModelModel struct {
@@ -21,12 +29,25 @@ type LocalModelModel struct {
 	showInput   bool
 	errorMsg    string
 	currentStep int
+	testing     bool
+	report      *model.Report
+}
+
+// selfTestMsg carries the result of the Test Model step
+type selfTestMsg struct {
+	report model.Report
 }
 
 func NewLocalModelModel(cfg *config.Config) *LocalModelModel {
 	pathInput := textinput.New()
 	pathInput.Placeholder = "Enter path to model weights"
 	pathInput.Width = 50
+	pathInput.SetValue(cfg.Profile().ModelPath)
+
+	currentStep := 1
+	if cfg.Profile().ModelPath != "" {
+		currentStep = 3
+	}
 
 	return &LocalModelModel{
 		choices: []string{
@@ -38,7 +59,7 @@ func NewLocalModelModel(cfg *config.Config) *LocalModelModel {
 		cursor:      0,
 		config:      cfg,
 		pathInput:   pathInput,
-		currentStep: 1,
+		currentStep: currentStep,
 	}
 }
 
@@ -54,89 +75,135 @@ func (m *LocalModelModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.UpdateSize(msg.Width, msg.Height)
 		return m, nil
 
+	case selfTestMsg:
+		m.testing = false
+		m.report = &msg.report
+		logging.For("model").Info("self-test finished", "path", msg.report.Path, "ok", msg.report.OK, "failed", msg.report.Failed)
+		if msg.report.OK {
+			m.currentStep = 4
+		} else {
+			m.currentStep = 3
+		}
+		return m, nil
+
 	case tea.KeyMsg:
+		k := m.Keys()
 		if m.showInput {
 			m.pathInput, cmd = m.pathInput.Update(msg)
 
-			switch msg.String() {
-			case "enter":
+			switch {
+			case key.Matches(msg, k.Confirm):
 				// TODO: Validate model path
+				p := m.config.Profile()
+				old := p.ModelPath
+				p.ModelPath = m.pathInput.Value()
+				m.errorMsg = ""
+				if err := config.Save(m.config); err != nil {
+					p.ModelPath = old
+					m.errorMsg = m.T("Error saving config: %v", err)
+					return m, nil
+				}
 				m.showInput = false
 				m.currentStep = 3
+				m.report = nil
 				return m, nil
-			case "esc":
+			case key.Matches(msg, k.Cancel):
 				m.showInput = false
+				m.errorMsg = ""
 				return m, nil
 			}
 			return m, cmd
 		}
 
-		switch msg.String() {
-		case "ctrl+c", "q":
+		if !m.showHelp && m.ContentKey(msg) {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, k.Quit):
 			m.quit = true
 			return m, tea.Quit
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case "down", "j":
-			if m.cursor < len(m.choices)-1 {
-				m.cursor++
-			}
-		case "enter", " ":
-			switch m.cursor {
-			case 0: // Download Model
-				m.currentStep = 2
-			case 1: // Configure Model Path
-				m.showInput = true
-				m.pathInput.Focus()
-				return m, textinput.Blink
-			case 2: // Test Model
-				if m.currentStep < 3 {
-					m.errorMsg = "Please complete previous steps first"
-				} else {
-					// TODO: Implement model testing
-					m.errorMsg = ""
-				}
-			case 3: // Back to Settings
-				return NewSettingsModel(), nil
-			}
-		case "?":
+		case key.Matches(msg, k.Up):
+			m.MoveCursor(&m.cursor, -1, len(m.choices))
+		case key.Matches(msg, k.Down):
+			m.MoveCursor(&m.cursor, 1, len(m.choices))
+		case key.Matches(msg, k.Select):
+			return m.choose()
+		case key.Matches(msg, k.Help):
 			m.showHelp = !m.showHelp
-		case "esc":
-			return NewSettingsModel(), nil
+		case key.Matches(msg, k.Back):
+			return NewSettingsModel(m.config), nil
+		}
+
+	case tea.MouseMsg:
+		if m.showHelp || m.showInput {
+			return m, nil
+		}
+		if m.MenuMouse(msg, &m.cursor, len(m.choices)) {
+			return m.choose()
 		}
 	}
 
 	return m, nil
 }
 
+// choose runs the setup step under the cursor
+func (m *LocalModelModel) choose() (tea.Model, tea.Cmd) {
+	switch m.cursor {
+	case 0: // Download Model
+		m.currentStep = 2
+	case 1: // Configure Model Path
+		m.showInput = true
+		m.pathInput.Focus()
+		return m, textinput.Blink
+	case 2: // Test Model
+		if m.currentStep < 3 {
+			m.errorMsg = m.T("Please complete previous steps first")
+		} else if !m.testing {
+			m.errorMsg = ""
+			m.testing = true
+			return m, m.selfTest()
+		}
+	case 3: // Back to Settings
+		return NewSettingsModel(m.config), nil
+	}
+	return m, nil
+}
+
 func (m *LocalModelModel) View() string {
+	st := m.Styles()
+	k := m.Keys()
 	if m.showHelp {
 		return m.CenterView(
-			styles.DialogBox.Render(
-				styles.AppTitle.Render("Local Model Setup Help") + "\n\n" +
-					"Steps to setup your local model:\n\n" +
-					"1. Download a compatible model (e.g., Llama2)\n" +
-					"2. Configure the path to model weights\n" +
-					"3. Test the model connection\n\n" +
-					"Navigation:\n" +
-					"• Up/Down or j/k: Move cursor\n" +
-					"• Enter/Space: Select option\n" +
-					"• ?: Toggle help\n" +
-					"• Esc: Back to settings\n\n" +
-					styles.PageFooter.Render("Press ? to close help"),
+			st.DialogBox.Render(
+				st.AppTitle.Render(m.T("Local Model Setup Help")) + "\n\n" +
+					m.T("Steps to setup your local model:\n\n"+
+						"1. Download a compatible model (e.g., Llama2)\n"+
+						"2. Configure the path to model weights\n"+
+						"3. Test the model connection") + "\n\n" +
+					m.FullHelp(
+						[]key.Binding{k.Up, k.Down, k.Select, k.Palette},
+						[]key.Binding{k.Help, keys.Describe(k.Back, "back to settings"), k.Quit},
+						[]key.Binding{k.Focus, k.PageUp, k.PageDown},
+					) + "\n\n" +
+					st.PageFooter.Render(m.ShortHelp(keys.Describe(k.Help, "close help"))),
 			),
 		)
 	}
 
 	if m.showInput {
+		m.pathInput.Placeholder = m.T("Enter path to model weights")
 		return m.CenterView(
-			styles.DialogBox.Render(
-				styles.AppTitle.Render("Configure Model Path") + "\n\n" +
-					styles.MenuOption.Render("Enter the path to your model weights:") + "\n" +
-					styles.InputBox.Render(m.pathInput.View()) + "\n\n" +
-					styles.PageFooter.Render("enter to save • esc to cancel"),
+			st.DialogBox.Render(
+				st.AppTitle.Render(m.T("Configure Model Path")) + "\n\n" +
+					st.MenuOption.Render(m.T("Enter the path to your model weights:")) + "\n" +
+					st.InputBox.Render(m.pathInput.View()) + "\n" +
+					(func() string {
+						if m.errorMsg != "" {
+							return "\n" + st.ErrorText.Render(m.errorMsg)
+						}
+						return ""
+					})() + "\n\n" +
+					st.PageFooter.Render(m.ShortHelp(k.Confirm, k.Cancel)),
 			),
 		)
 	}
@@ -144,85 +211,75 @@ func (m *LocalModelModel) View() string {
 	// Left panel - Menu options
 	var menuContent string
 	for i, choice := range m.choices {
-		cursor := "  "
-		if m.cursor == i {
-			cursor = "> "
-		}
-
-		status := getStepStatus(m.currentStep, i+1)
+		status := m.stepStatus(m.currentStep, i+1)
 		icon := getStepIcon(m.currentStep, i+1)
-
-		option := icon + " " + choice + " " + status
-		if m.cursor == i {
-			menuContent += styles.HighlightedOption.Render(cursor+option) + "\n"
-		} else {
-			menuContent += styles.MenuOption.Render(cursor+option) + "\n"
-		}
+		menuContent += m.MenuOption(i, m.cursor, icon+" "+m.T(choice)+" "+status) + "\n"
 	}
 
-	menuView := styles.MenuBox.Render(
-		styles.SectionTitle.Render("Setup Steps") + "\n\n" +
-			menuContent + "\n\n" +
-			styles.PageFooter.Render("? for help • esc to go back"),
+	menuView := st.MenuBox.Render(
+		st.SectionTitle.Render(m.T("Setup Steps")) + "\n\n" +
+			menuContent + "\n" +
+			st.MenuFooter.Render(m.ShortHelp(k.Help, k.Back)),
 	)
 
 	// Right panel - Detailed content
 	var detailContent string
 	switch m.cursor {
 	case 0: // Download Model
-		detailContent = styles.MainTitle.Render("Download Compatible Model") + "\n\n" +
-			styles.Description.Render(
-				"Choose and download one of these models:\n\n"+
+		detailContent = st.MainTitle.Render(m.T("Download Compatible Model")) + "\n\n" +
+			st.Description.Render(
+				m.T("Choose and download one of these models:")+"\n\n"+
 					"🤖 Llama 2\n"+
-					"  • Size: 7B/13B/70B parameters\n"+
-					"  • License: Meta AI Research License\n"+
+					"  • "+m.T("Size: %s parameters", "7B/13B/70B")+"\n"+
+					"  • "+m.T("License: %s", "Meta AI Research License")+"\n"+
 					"  • URL: huggingface.co/meta-llama\n\n"+
 					"🤖 GPT-J\n"+
-					"  • Size: 6B parameters\n"+
-					"  • License: Apache 2.0\n"+
+					"  • "+m.T("Size: %s parameters", "6B")+"\n"+
+					"  • "+m.T("License: %s", "Apache 2.0")+"\n"+
 					"  • URL: huggingface.co/EleutherAI\n\n"+
 					"🤖 BLOOM\n"+
-					"  • Size: 7B parameters\n"+
-					"  • License: OpenRAIL-M\n"+
+					"  • "+m.T("Size: %s parameters", "7B")+"\n"+
+					"  • "+m.T("License: %s", "OpenRAIL-M")+"\n"+
 					"  • URL: huggingface.co/bigscience\n",
 			)
 
 	case 1: // Configure Model Path
-		detailContent = styles.MainTitle.Render("Model Path Configuration") + "\n\n" +
-			styles.Description.Render(
-				"Set up the path to your downloaded model:\n\n"+
+		detailContent = st.MainTitle.Render(m.T("Model Path Configuration")) + "\n\n" +
+			st.Description.Render(
+				m.T("Set up the path to your downloaded model:\n\n"+
 					"1. Locate your downloaded model files\n"+
 					"2. Copy the full path to the weights file\n"+
-					"3. Press ENTER to open the path input\n"+
+					"3. Press %s to open the path input\n"+
 					"4. Paste or type the path\n\n"+
-					"Example paths:\n"+
+					"Example paths:", m.Keys().Select.Help().Key)+"\n"+
 					"• ~/.cache/huggingface/llama2-7b\n"+
 					"• ~/models/gpt-j-6B/weights\n"+
 					"• /opt/models/bloom-7b1\n",
 			)
 
 	case 2: // Test Model
-		detailContent = styles.MainTitle.Render("Model Testing") + "\n\n" +
-			styles.Description.Render(
-				"Verify your model configuration:\n\n"+
+		detailContent = st.MainTitle.Render(m.T("Model Testing")) + "\n\n" +
+			st.Description.Render(
+				m.T("Verify your model configuration:\n\n"+
 					"• Check model file accessibility\n"+
 					"• Validate model format\n"+
 					"• Test basic inference\n"+
-					"• Measure performance\n\n"+
-					"Status: "+getTestStatus(m.currentStep),
-			)
+					"• Measure performance")+"\n\n"+
+					m.T("Server: %s", m.config.Profile().Server())+"\n"+
+					m.T("Status: %s", m.testStatus(st)),
+			) + m.reportView(st)
 	}
 
 	if m.errorMsg != "" {
-		detailContent += "\n\n" + styles.ErrorText.Render(m.errorMsg)
+		detailContent += "\n\n" + st.ErrorText.Render(m.errorMsg)
 	}
 
 	// Add progress bar
 	progress := float64(m.currentStep-1) / 3.0
-	detailContent += "\n\n" + styles.SectionTitle.Render("Setup Progress") + "\n" +
-		renderProgressBar(progress, 40)
+	detailContent += "\n\n" + st.SectionTitle.Render(m.T("Setup Progress")) + "\n" +
+		renderProgressBar(st, progress, st.ContentBox.GetWidth()-st.ContentBox.GetHorizontalPadding())
 
-	detailView := styles.ContentBox.Render(detailContent)
+	detailView := m.ContentView(detailContent)
 
 	// Combine views
 	return m.SplitView(menuView, detailView)
@@ -240,35 +297,69 @@ func getStepIcon(currentStep, step int) string {
 	return "○"
 }
 
-func getTestStatus(currentStep int) string {
-	if currentStep < 3 {
-		return styles.WarningText.Render("NOT READY")
+// selfTest runs the same checks as `mainframe model test`
+func (m *LocalModelModel) selfTest() tea.Cmd {
+	path := m.config.Profile().ModelPath
+	srv := model.NewServer(m.config.Profile().Server())
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		return selfTestMsg{report: model.SelfTest(ctx, path, srv)}
+	}
+}
+
+func (m *LocalModelModel) testStatus(st *styles.Styles) string {
+	switch {
+	case m.testing:
+		return st.WarningText.Render(m.T("TESTING..."))
+	case m.report != nil && m.report.OK:
+		return st.SuccessText.Render(m.T("PASSED"))
+	case m.report != nil:
+		return st.ErrorText.Render(m.T("FAILED"))
+	case m.currentStep < 3:
+		return st.WarningText.Render(m.T("NOT READY"))
 	}
-	return styles.SuccessText.Render("READY TO TEST")
+	return st.SuccessText.Render(m.T("READY TO TEST"))
 }
 
-func getStepStatus(currentStep, step int) string {
+// reportView lists each step of the last self-test with its timing
+func (m *LocalModelModel) reportView(st *styles.Styles) string {
+	if m.report == nil || m.testing {
+		return ""
+	}
+	var lines string
+	for _, step := range m.report.Steps {
+		if step.OK {
+			lines += st.SuccessText.Render("✓ ") + fmt.Sprintf("%-10s %7.1fms  %s", step.Name, step.DurationMS, step.Detail) + "\n"
+		} else {
+			lines += st.ErrorText.Render("✗ "+step.Name+": "+step.Error) + "\n"
+		}
+	}
+	return "\n\n" + lines
+}
+
+func (m *LocalModelModel) stepStatus(currentStep, step int) string {
+	st := m.Styles()
 	if currentStep > step {
-		return styles.SuccessText.Render("COMPLETED")
+		return st.SuccessText.Render(m.T("COMPLETED"))
 	} else if currentStep == step {
-		return styles.WarningText.Render("IN PROGRESS")
+		return st.WarningText.Render(m.T("IN PROGRESS"))
 	}
-	return "NOT STARTED"
+	return m.T("NOT STARTED")
 }
 
-func renderProgressBar(progress float64, width int) string {
+// progressBarWidth is the longest the bar itself is drawn
+const progressBarWidth = 40
+
+// renderProgressBar draws the bar with its percentage on the same line, in
+// at most width columns
+func renderProgressBar(st *styles.Styles, progress float64, width int) string {
+	percentage := int(progress*100 + 0.5)
+	label := "  " + st.SuccessText.Render("["+st.HighlightedOption.Render(fmt.Sprintf("%d%%", percentage))+"]")
+
+	width = max(min(progressBarWidth, width-lipgloss.Width(label)), 1)
 	filled := int(progress * float64(width))
-	empty := width - filled
-
-	bar := styles.SuccessText.Render(strings.Repeat("█", filled))
-	bar += styles.Description.Render(strings.Repeat("░", empty))
-	percentage := int(progress * 100)
-
-	return bar + styles.Description.Render(
-		"  "+styles.SuccessText.Render(
-			"["+styles.HighlightedOption.Render(
-				""+string(rune('0'+percentage/10))+string(rune('0'+percentage%10))+"%",
-			)+"]",
-		),
-	)
+	bar := st.SuccessText.Render(strings.Repeat("█", filled))
+	bar += st.Description.Copy().UnsetMargins().Render(strings.Repeat("░", width-filled))
+	return bar + label
 }
@@ -0,0 +1,481 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"mainframe/pkg/config"
+	"mainframe/pkg/logging"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tailMsg asks the log viewer to read lines appended to the current file
+type tailMsg struct{}
+
+var logLevels = []string{"", "DEBUG", "INFO", "WARN", "ERROR"}
+
+// LogViewerModel reads the log files in the logs directory, following the
+// current one as it is written
+type LogViewerModel struct {
+	BaseModel
+	config  *config.Config
+	dir     string
+	files   []string
+	file    int
+	entries []logging.Entry
+	offset  int64
+
+	// shown maps lines in the viewport to entries after filtering
+	shown      []int
+	level      int
+	components []string
+	component  int
+	query      string
+	matches    []int
+	match      int
+
+	viewport  viewport.Model
+	input     textinput.Model
+	inputMode string
+	errorMsg  string
+}
+
+func NewLogViewerModel(cfg *config.Config) *LogViewerModel {
+	input := textinput.New()
+	input.Width = 40
+
+	m := &LogViewerModel{
+		config:     cfg,
+		dir:        cfg.LogsDir(),
+		components: []string{""},
+		viewport:   viewport.New(0, 0),
+		input:      input,
+	}
+	m.loadFiles()
+	return m
+}
+
+func (m *LogViewerModel) Init() tea.Cmd {
+	if !m.config.Enabled(config.FeatureLogFollow, m.Now()) {
+		return nil
+	}
+	return m.tail()
+}
+
+func (m *LogViewerModel) tail() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return tailMsg{}
+	})
+}
+
+// live reports whether the open file is the one being written to
+func (m *LogViewerModel) live() bool {
+	return len(m.files) > 0 && filepath.Base(m.files[m.file]) == logging.CurrentFile
+}
+
+func (m *LogViewerModel) loadFiles() {
+	files, err := logging.Files(m.dir)
+	if err != nil {
+		m.errorMsg = err.Error()
+		return
+	}
+	m.files = files
+	m.file = 0
+	m.open()
+}
+
+// open reads the selected file from the start
+func (m *LogViewerModel) open() {
+	m.entries, m.offset = nil, 0
+	if len(m.files) == 0 {
+		// header explains that there are no logs yet
+		m.refresh()
+		return
+	}
+
+	entries, n, err := logging.ReadFile(m.files[m.file])
+	m.errorMsg = ""
+	if err != nil {
+		m.errorMsg = err.Error()
+	}
+	m.entries, m.offset = entries, n
+	m.collectComponents()
+	m.refresh()
+	m.viewport.GotoBottom()
+}
+
+func (m *LogViewerModel) collectComponents() {
+	seen := map[string]bool{}
+	m.components = []string{""}
+	for _, e := range m.entries {
+		if e.Component != "" && !seen[e.Component] {
+			seen[e.Component] = true
+			m.components = append(m.components, e.Component)
+		}
+	}
+	if m.component >= len(m.components) {
+		m.component = 0
+	}
+}
+
+// refresh filters the entries and redraws the viewport content
+func (m *LogViewerModel) refresh() {
+	st := m.Styles()
+	minLevel := m.level
+	component := m.components[m.component]
+	query := strings.ToLower(m.query)
+
+	m.shown, m.matches = nil, nil
+	var lines []string
+	for i, e := range m.entries {
+		if minLevel > 0 && levelRank(e.Level) < minLevel {
+			continue
+		}
+		if component != "" && e.Component != component {
+			continue
+		}
+		if query != "" && strings.Contains(strings.ToLower(e.Raw), query) {
+			m.matches = append(m.matches, len(m.shown))
+		}
+		m.shown = append(m.shown, i)
+		lines = append(lines, m.renderEntry(e))
+	}
+	if m.match >= len(m.matches) {
+		m.match = 0
+	}
+
+	if len(lines) == 0 {
+		lines = []string{st.Description.Render(m.T("No entries match the current filters"))}
+	}
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+func levelRank(level string) int {
+	for i, l := range logLevels {
+		if l != "" && strings.EqualFold(l, level) {
+			return i
+		}
+	}
+	return 0
+}
+
+// ago shortens d to its largest unit, such as 5m or 3d
+func ago(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return fmt.Sprintf("%ds", max(d, 0)/time.Second)
+}
+
+func (m *LogViewerModel) renderEntry(e logging.Entry) string {
+	st := m.Styles()
+
+	stamp := "        "
+	switch {
+	case e.Time.IsZero():
+	case m.config.Enabled(config.FeatureLogRelativeTime, m.Now()):
+		stamp = fmt.Sprintf("%8s", m.T("%s ago", ago(m.Now().Sub(e.Time))))
+	default:
+		stamp = e.Time.Local().Format("15:04:05")
+	}
+
+	level := fmt.Sprintf("%-5s", e.Level)
+	switch strings.ToUpper(e.Level) {
+	case "ERROR":
+		level = st.ErrorText.Render(level)
+	case "WARN":
+		level = st.WarningText.Render(level)
+	case "INFO":
+		level = st.SuccessText.Render(level)
+	}
+
+	text := e.Message
+	if e.Component != "" {
+		text = "[" + e.Component + "] " + text
+	}
+	if e.Attrs != "" {
+		text += "  " + e.Attrs
+	}
+
+	line := stamp + " " + level + " " + m.highlight(text)
+	if m.viewport.Width > 0 {
+		line = st.Renderer().NewStyle().MaxWidth(m.viewport.Width).Render(line)
+	}
+	return line
+}
+
+// highlight marks every occurrence of the search query in text
+func (m *LogViewerModel) highlight(text string) string {
+	if m.query == "" {
+		return text
+	}
+	mark := m.Styles().Renderer().NewStyle().Reverse(true)
+	lower, query := strings.ToLower(text), strings.ToLower(m.query)
+	// Case folding changed byte offsets, match exactly instead
+	if len(lower) != len(text) || len(query) != len(m.query) {
+		lower, query = text, m.query
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, query)
+		if i < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:i])
+		b.WriteString(mark.Render(text[i : i+len(query)]))
+		text, lower = text[i+len(query):], lower[i+len(query):]
+	}
+}
+
+func (m *LogViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case WindowSizeMsg:
+		m.UpdateSize(msg.Width, msg.Height)
+		follow := m.viewport.AtBottom()
+		m.layout()
+		m.refresh()
+		if follow {
+			m.viewport.GotoBottom()
+		}
+		return m, nil
+
+	case tailMsg:
+		if !m.live() {
+			if len(m.files) == 0 {
+				m.loadFiles()
+			}
+			return m, m.tail()
+		}
+		entries, offset, err := logging.ReadFrom(m.files[m.file], m.offset)
+		if err != nil {
+			// The file was rotated away, pick up the new one
+			m.loadFiles()
+			return m, m.tail()
+		}
+		if offset < m.offset {
+			m.entries = nil
+		}
+		m.offset = offset
+		if len(entries) > 0 {
+			follow := m.viewport.AtBottom()
+			m.entries = append(m.entries, entries...)
+			m.collectComponents()
+			m.refresh()
+			if follow {
+				m.viewport.GotoBottom()
+			}
+		}
+		return m, m.tail()
+
+	case tea.KeyMsg:
+		if m.inputMode != "" {
+			return m.updateInput(msg)
+		}
+
+		k := m.Keys()
+		switch {
+		case key.Matches(msg, k.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, k.Back):
+			if m.query != "" {
+				m.query = ""
+				m.refresh()
+				return m, nil
+			}
+			return NewDeveloperModel(m.config), nil
+		case key.Matches(msg, k.Level):
+			m.level = (m.level + 1) % len(logLevels)
+			m.refresh()
+		case key.Matches(msg, k.Component):
+			m.component = (m.component + 1) % len(m.components)
+			m.refresh()
+		case key.Matches(msg, k.Search):
+			return m, m.startInput("search", m.T("Search"))
+		case key.Matches(msg, k.JumpTime):
+			return m, m.startInput("time", m.T("15:04, 15:04:05 or 2006-01-02 15:04"))
+		case key.Matches(msg, k.NextMatch):
+			m.jumpMatch(1)
+		case key.Matches(msg, k.PrevMatch):
+			m.jumpMatch(-1)
+		case key.Matches(msg, k.OlderFile):
+			if m.file < len(m.files)-1 {
+				m.file++
+				m.open()
+			}
+		case key.Matches(msg, k.NewerFile):
+			if m.file > 0 {
+				m.file--
+				m.open()
+			}
+		case key.Matches(msg, k.Bottom):
+			m.viewport.GotoBottom()
+		case key.Matches(msg, k.Top):
+			m.viewport.GotoTop()
+		default:
+			m.viewport.KeyMap.Up, m.viewport.KeyMap.Down = k.Up, k.Down
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+		m.layout()
+	}
+
+	return m, nil
+}
+
+func (m *LogViewerModel) startInput(mode, placeholder string) tea.Cmd {
+	m.inputMode = mode
+	m.input.Placeholder = placeholder
+	m.input.SetValue("")
+	if mode == "search" {
+		m.input.SetValue(m.query)
+	}
+	m.input.Focus()
+	m.layout()
+	return textinput.Blink
+}
+
+func (m *LogViewerModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.Keys().Confirm):
+		value := strings.TrimSpace(m.input.Value())
+		m.errorMsg = ""
+		switch m.inputMode {
+		case "search":
+			m.query = value
+			m.match = 0
+			m.refresh()
+			m.jumpMatch(0)
+		case "time":
+			if err := m.jumpTime(value); err != nil {
+				m.errorMsg = err.Error()
+			}
+		}
+		m.inputMode = ""
+		m.layout()
+		return m, nil
+	case key.Matches(msg, m.Keys().Cancel):
+		m.inputMode = ""
+		m.layout()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// jumpMatch scrolls to the next (1), previous (-1) or current (0) match
+func (m *LogViewerModel) jumpMatch(step int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.match = (m.match + step + len(m.matches)) % len(m.matches)
+	m.viewport.SetYOffset(m.matches[m.match])
+}
+
+// jumpTime scrolls to the first shown entry at or after the given time.
+// A time of day alone refers to the day of the newest entry.
+func (m *LogViewerModel) jumpTime(value string) error {
+	if len(m.shown) == 0 {
+		return nil
+	}
+	day := m.entries[m.shown[len(m.shown)-1]].Time.Local()
+
+	var target time.Time
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			target = time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local)
+		}
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02 15:04:05", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			target = t
+		}
+	}
+	if target.IsZero() {
+		return errors.New(m.T("cannot read %q as a time", value))
+	}
+
+	for line, i := range m.shown {
+		if !m.entries[i].Time.Before(target) {
+			m.viewport.SetYOffset(line)
+			return nil
+		}
+	}
+	m.viewport.GotoBottom()
+	return nil
+}
+
+func (m *LogViewerModel) header() string {
+	st := m.Styles()
+	name := m.T("(none)")
+	if len(m.files) > 0 {
+		name = filepath.Base(m.files[m.file])
+		if m.live() && m.config.Enabled(config.FeatureLogFollow, m.Now()) {
+			name += " " + m.T("(live)")
+		}
+	}
+
+	level := logLevels[m.level]
+	if level == "" {
+		level = m.T("all")
+	}
+	component := m.components[m.component]
+	if component == "" {
+		component = m.T("all")
+	}
+	status := m.T("File: %s  •  Level ≥ %s  •  Component: %s  •  %d/%d entries",
+		name, level, component, len(m.shown), len(m.entries))
+	if m.query != "" {
+		status += m.T("  •  %q: %d matches", m.query, len(m.matches))
+	}
+
+	header := st.SectionTitle.Render(m.T("Logs")) + "\n" + st.StatusIndicator.Render(status)
+	switch {
+	case m.errorMsg != "":
+		header += "\n" + st.ErrorText.Render(m.errorMsg)
+	case len(m.files) == 0:
+		header += "\n" + st.ErrorText.Render(m.T("No logs yet. Turn on Log Output to start writing them to %s", m.dir))
+	}
+	return header
+}
+
+func (m *LogViewerModel) footer() string {
+	st := m.Styles()
+	if m.inputMode != "" {
+		return st.InputBox.Render(m.input.View())
+	}
+	k := m.Keys()
+	return st.PageFooter.Render(fmt.Sprintf("%3.0f%% • ", m.viewport.ScrollPercent()*100) + m.ShortHelp(
+		k.Search, k.NextMatch, k.PrevMatch, k.JumpTime, k.Level, k.Component, k.OlderFile, k.NewerFile, k.Back,
+	))
+}
+
+// layout sizes the viewport to the space between header and footer
+func (m *LogViewerModel) layout() {
+	m.viewport.Width = max(m.width-4, 0)
+	m.viewport.Height = max(m.height-2-lipgloss.Height(m.header())-lipgloss.Height(m.footer()), 1)
+}
+
+func (m *LogViewerModel) View() string {
+	st := m.Styles()
+	return st.DocStyle.Render(
+		lipgloss.JoinVertical(lipgloss.Left, m.header(), m.viewport.View(), m.footer()),
+	)
+}
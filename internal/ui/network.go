@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"mainframe/pkg/config"
+	"mainframe/pkg/keys"
+	"mainframe/pkg/logging"
+	"mainframe/pkg/netdiag"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// netdiagMsg carries the result of a diagnostics run
+type netdiagMsg struct {
+	report netdiag.Report
+}
+
+// NetworkModel checks that the configured AI endpoint can be reached
+type NetworkModel struct {
+	BaseModel
+	config  *config.Config
+	running bool
+	report  *netdiag.Report
+}
+
+func NewNetworkModel(cfg *config.Config) *NetworkModel {
+	return &NetworkModel{config: cfg}
+}
+
+func (m *NetworkModel) Init() tea.Cmd {
+	return m.run()
+}
+
+func (m *NetworkModel) run() tea.Cmd {
+	m.running = true
+	endpoint := m.config.Profile().Endpoint()
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		return netdiagMsg{report: netdiag.Run(ctx, endpoint, netdiag.Options{})}
+	}
+}
+
+func (m *NetworkModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case WindowSizeMsg:
+		m.UpdateSize(msg.Width, msg.Height)
+		return m, nil
+
+	case netdiagMsg:
+		m.running = false
+		m.report = &msg.report
+		logging.For("netdiag").Info("diagnostics finished", "endpoint", msg.report.Endpoint, "ok", msg.report.OK())
+		return m, nil
+
+	case tea.KeyMsg:
+		k := m.Keys()
+		switch {
+		case key.Matches(msg, k.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, k.Refresh, k.Select):
+			if !m.running {
+				return m, m.run()
+			}
+		case key.Matches(msg, k.Back):
+			return NewDeveloperModel(m.config), nil
+		}
+	}
+	return m, nil
+}
+
+func (m *NetworkModel) View() string {
+	st := m.Styles()
+	p := m.config.Profile()
+
+	target := m.T("OpenAI API")
+	if p.AIModel == "local" {
+		target = m.T("local model server")
+	}
+	content := st.SectionTitle.Render(m.T("Network Diagnostics")) + "\n" +
+		st.Description.Render(m.T("Endpoint: %s (%s)", p.Endpoint(), target))
+
+	if m.running || m.report == nil {
+		content += "\n" + st.WarningText.Render(m.T("Running checks..."))
+	} else {
+		content += "\n"
+		for _, c := range m.report.Checks {
+			switch {
+			case c.Skipped:
+				content += fmt.Sprintf("- %-5s %9s  %s\n", c.Name, "", c.Detail)
+			case c.OK:
+				content += st.SuccessText.Render("✓ ") + fmt.Sprintf("%-5s %9s  %s\n", c.Name, formatDuration(c.Duration), c.Detail)
+			default:
+				content += st.ErrorText.Render("✗ ") + fmt.Sprintf("%-5s %9s  ", c.Name, formatDuration(c.Duration)) + st.ErrorText.Render(c.Error) + "\n"
+				if c.Hint != "" {
+					content += fmt.Sprintf("  %-5s %9s  %s\n", "", "", st.WarningText.Render(c.Hint))
+				}
+			}
+		}
+
+		proxy := m.report.Proxy
+		used := m.T("none, connecting directly")
+		if proxy.Used != "" {
+			used = proxy.Used
+		}
+		content += "\n" + st.SectionTitle.Render(m.T("Proxy")) + "\n" +
+			m.T("HTTP_PROXY:  %s\nHTTPS_PROXY: %s\nNO_PROXY:    %s\nUsed:        %s\n",
+				m.orUnset(proxy.HTTPProxy), m.orUnset(proxy.HTTPSProxy), m.orUnset(proxy.NoProxy), used)
+	}
+
+	return st.DocStyle.Render(content + "\n" + st.PageFooter.Render(m.ShortHelp(keys.Describe(m.Keys().Refresh, "run again"), m.Keys().Back)))
+}
@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"errors"
+	"mainframe/pkg/config"
+	"mainframe/pkg/keys"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteRows is how many matches the palette shows at once
+const paletteRows = 8
+
+// paletteMove is the help for moving through the matches. Letter keys of
+// the up and down bindings type into the search instead.
+var paletteMove = key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "move"))
+
+// paletteEntry is something the command palette can jump to or run
+type paletteEntry struct {
+	title string
+	// kind is shown next to the title: screen, toggle or action
+	kind  string
+	state string
+	run   func(m *AppModel) tea.Cmd
+}
+
+// paletteState is what the app remembers while the palette is open
+type paletteState struct {
+	open    bool
+	input   textinput.Model
+	entries []paletteEntry
+	matches []paletteEntry
+	cursor  int
+}
+
+// openPalette shows the palette with every entry as it is right now
+func (m *AppModel) openPalette() tea.Cmd {
+	input := textinput.New()
+	input.Placeholder = m.T("Type to search screens, toggles and actions")
+	input.Prompt = "> "
+	input.Width = 40
+	input.Focus()
+	m.palette = paletteState{open: true, input: input, entries: m.paletteEntries()}
+	m.palette.filter()
+	return textinput.Blink
+}
+
+func (m *AppModel) paletteEntries() []paletteEntry {
+	screen := func(title string, open func(*config.Config) tea.Model) paletteEntry {
+		return paletteEntry{title: m.T(title), kind: m.T("screen"), run: func(m *AppModel) tea.Cmd {
+			return m.navigate(open(m.config))
+		}}
+	}
+	entries := []paletteEntry{
+		screen("Home", func(c *config.Config) tea.Model { return NewHomeModel(c) }),
+		screen("Settings", func(c *config.Config) tea.Model { return NewSettingsModel(c) }),
+		screen("Model Configuration", func(c *config.Config) tea.Model { return NewLocalModelModel(c) }),
+		screen("Developer Options", func(c *config.Config) tea.Model { return NewDeveloperModel(c) }),
+		screen("Feature Flags", func(c *config.Config) tea.Model { return NewFeaturesModel(c) }),
+		screen("Performance Metrics", func(c *config.Config) tea.Model { return NewPerformanceModel(c) }),
+		screen("Network Diagnostics", func(c *config.Config) tea.Model { return NewNetworkModel(c) }),
+		screen("Logs", func(c *config.Config) tea.Model { return NewLogViewerModel(c) }),
+	}
+
+	p := m.config.Profile()
+	toggle := func(title string, on bool, flip func() error) paletteEntry {
+		return paletteEntry{title: title, kind: m.T("toggle"), state: m.T(onOffText(on)), run: func(m *AppModel) tea.Cmd {
+			m.setError(errors.Join(flip(), config.Save(m.config), m.applyLogging()))
+			return nil
+		}}
+	}
+	entries = append(entries,
+		toggle(m.T("Debug Mode"), p.Debug, func() error { p.Debug = !p.Debug; return nil }),
+		toggle(m.T("Log Output"), p.Logs, func() error { p.Logs = !p.Logs; return nil }),
+		toggle(m.T("Experimental"), p.Experimental, func() error { p.Experimental = !p.Experimental; return nil }),
+	)
+	entries = append(entries, paletteEntry{title: m.T("Accessible Mode"), kind: m.T("toggle"), state: m.T(onOffText(m.config.Accessible)), run: func(m *AppModel) tea.Cmd {
+		m.config.Accessible = !m.config.Accessible
+		m.setError(errors.Join(config.Save(m.config), m.applyStyles()))
+		// Announcements and screen readers need the normal screen
+		if m.config.Accessible {
+			return tea.Batch(tea.ExitAltScreen, tea.DisableMouse)
+		}
+		return tea.Batch(tea.EnterAltScreen, tea.EnableMouseCellMotion)
+	}})
+	for _, f := range config.Features() {
+		if f.Expired(m.Now()) {
+			continue
+		}
+		name, on := f.Name, m.config.Enabled(f.Name, m.Now())
+		entries = append(entries, toggle(name, on, func() error { return m.config.SetFeature(name, !on) }))
+	}
+
+	return append(entries,
+		paletteEntry{title: m.T("Test Model"), kind: m.T("action"), run: func(m *AppModel) tea.Cmd {
+			lm := NewLocalModelModel(m.config)
+			lm.cursor = 2
+			cmd := m.navigate(lm)
+			_, test := lm.choose()
+			return tea.Batch(cmd, test)
+		}},
+		paletteEntry{title: m.T("Configure API Key"), kind: m.T("action"), run: func(m *AppModel) tea.Cmd {
+			s := NewSettingsModel(m.config)
+			s.cursor = 2
+			s.showAPIInput = true
+			s.apiKeyInput.Focus()
+			return tea.Batch(m.navigate(s), textinput.Blink)
+		}},
+		paletteEntry{title: m.T("Quit"), kind: m.T("action"), run: func(*AppModel) tea.Cmd {
+			return tea.Quit
+		}},
+	)
+}
+
+// updatePalette handles keys while the palette is open
+func (m *AppModel) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	k := m.Keys()
+	pal := &m.palette
+	// Letters go to the search, so only keys that cannot be typed move
+	// through the matches
+	typed := msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace
+	switch {
+	case key.Matches(msg, k.Palette), key.Matches(msg, k.Cancel):
+		pal.open = false
+		return m, nil
+	case key.Matches(msg, k.Confirm):
+		pal.open = false
+		if pal.cursor < len(pal.matches) {
+			return m, pal.matches[pal.cursor].run(m)
+		}
+		return m, nil
+	case !typed && key.Matches(msg, k.Up):
+		pal.cursor = max(pal.cursor-1, 0)
+		return m, nil
+	case !typed && key.Matches(msg, k.Down):
+		pal.cursor = max(min(pal.cursor+1, len(pal.matches)-1), 0)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	query := pal.input.Value()
+	pal.input, cmd = pal.input.Update(msg)
+	if pal.input.Value() != query {
+		pal.filter()
+	}
+	return m, cmd
+}
+
+// filter keeps the entries matching the search, best match first
+func (p *paletteState) filter() {
+	query := p.input.Value()
+	type scored struct {
+		entry paletteEntry
+		score int
+	}
+	var found []scored
+	for _, e := range p.entries {
+		if score, ok := fuzzyScore(query, e.title+" "+e.kind); ok {
+			found = append(found, scored{e, score})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+
+	p.matches = p.matches[:0]
+	for _, f := range found {
+		p.matches = append(p.matches, f.entry)
+	}
+	p.cursor = 0
+}
+
+// fuzzyScore reports whether the letters of query appear in text in order,
+// ignoring case and spaces, and how well they match. Letters that follow
+// each other or start a word score higher.
+func fuzzyScore(query, text string) (int, bool) {
+	q := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	if len(q) == 0 {
+		return 0, true
+	}
+	score, qi, prevMatched := 0, 0, false
+	prev := ' '
+	for _, r := range strings.ToLower(text) {
+		if qi < len(q) && r == q[qi] {
+			score++
+			if prevMatched {
+				score += 3
+			}
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 2
+			}
+			qi++
+			prevMatched = true
+		} else {
+			prevMatched = false
+		}
+		prev = r
+	}
+	return score, qi == len(q)
+}
+
+func (m *AppModel) paletteView() string {
+	st := m.Styles()
+	k := m.Keys()
+	pal := m.palette
+
+	var list string
+	first := max(pal.cursor-paletteRows+1, 0)
+	for i := first; i < min(first+paletteRows, len(pal.matches)); i++ {
+		e := pal.matches[i]
+		line := e.title + " " + st.HelpDesc.Render(e.kind)
+		if e.state != "" {
+			line += " " + st.HelpDesc.Render("("+e.state+")")
+		}
+		if i == pal.cursor {
+			list += st.HighlightedOption.Render("> "+line) + "\n"
+		} else {
+			list += st.MenuOption.Render("  "+line) + "\n"
+		}
+	}
+	if len(pal.matches) == 0 {
+		list = st.MenuOption.Render("  "+m.T("No matches")) + "\n"
+	}
+
+	return m.CenterView(
+		st.DialogBox.Render(
+			st.SectionTitle.Render(m.T("Command Palette")) + "\n" +
+				st.InputBox.Render(pal.input.View()) + "\n\n" +
+				list + "\n" +
+				m.ShortHelp(paletteMove, keys.Describe(k.Confirm, "go"), keys.Describe(k.Cancel, "close")),
+		),
+	)
+}
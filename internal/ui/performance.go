@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fmt"
+	"mainframe/pkg/config"
+	"mainframe/pkg/metrics"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+)
+
+// sampleMsg asks the performance panel to take a sample
+type sampleMsg struct{}
+
+// PerformanceModel shows live process, rendering and host metrics
+type PerformanceModel struct {
+	BaseModel
+	config   *config.Config
+	sampler  *metrics.Sampler
+	errorMsg string
+}
+
+func NewPerformanceModel(cfg *config.Config) *PerformanceModel {
+	return &PerformanceModel{config: cfg}
+}
+
+func (m *PerformanceModel) Init() tea.Cmd {
+	m.samples()
+	return m.tick()
+}
+
+// samples returns the history, starting to sample on first use. The
+// session's recorder is only shared once the app has set up the screen,
+// so this cannot happen in the constructor.
+func (m *PerformanceModel) samples() []metrics.Sample {
+	if m.sampler == nil {
+		m.sampler = metrics.NewSampler(m.Metrics())
+		m.sampler.Sample()
+	}
+	return m.sampler.Samples()
+}
+
+func (m *PerformanceModel) tick() tea.Cmd {
+	return tea.Tick(m.config.SampleInterval(), func(time.Time) tea.Msg {
+		return sampleMsg{}
+	})
+}
+
+func (m *PerformanceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case WindowSizeMsg:
+		m.UpdateSize(msg.Width, msg.Height)
+		return m, nil
+
+	case sampleMsg:
+		m.samples()
+		m.sampler.Sample()
+		return m, m.tick()
+
+	case tea.KeyMsg:
+		k := m.Keys()
+		switch {
+		case key.Matches(msg, k.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, k.Faster):
+			m.setInterval(m.config.SampleInterval() / 2)
+		case key.Matches(msg, k.Slower):
+			m.setInterval(m.config.SampleInterval() * 2)
+		case key.Matches(msg, k.Reset):
+			m.Metrics().Reset()
+			m.sampler = metrics.NewSampler(m.Metrics())
+			m.sampler.Sample()
+		case key.Matches(msg, k.Back):
+			return NewDeveloperModel(m.config), nil
+		}
+	}
+	return m, nil
+}
+
+// setInterval changes how often samples are taken, starting with the
+// next tick
+func (m *PerformanceModel) setInterval(d time.Duration) {
+	d = min(max(d, config.MinMetricsInterval), time.Minute)
+	m.errorMsg = ""
+	if err := m.config.Set("metrics_interval", d.String()); err != nil {
+		m.errorMsg = err.Error()
+		return
+	}
+	if err := config.Save(m.config); err != nil {
+		m.errorMsg = err.Error()
+	}
+}
+
+func (m *PerformanceModel) View() string {
+	st := m.Styles()
+	samples := m.samples()
+	last := samples[len(samples)-1]
+	width := max(m.width/2-6, 20)
+	spark := max(width-24, 10)
+
+	series := func(get func(metrics.Sample) float64) []float64 {
+		values := make([]float64, len(samples))
+		for i, s := range samples {
+			values[i] = get(s)
+		}
+		return values
+	}
+	row := func(label, value string, values []float64) string {
+		line := fmt.Sprintf("%-12s %10s  ", m.T(label), value)
+		// Sparklines have no words, and the value is printed already
+		if values != nil && !st.Accessible() {
+			line += st.SuccessText.Render(sparkline(values, spark))
+		}
+		return line + "\n"
+	}
+
+	// Left column - Process and rendering
+	frames := m.Metrics().Frames()
+	process := st.SectionTitle.Render(m.T("Process")) + "\n" +
+		row("RSS", formatBytes(last.RSS), series(func(s metrics.Sample) float64 { return float64(s.RSS) })) +
+		row("Heap", formatBytes(last.HeapAlloc), series(func(s metrics.Sample) float64 { return float64(s.HeapAlloc) })) +
+		row("Goroutines", fmt.Sprint(last.Goroutines), series(func(s metrics.Sample) float64 { return float64(s.Goroutines) })) +
+		row("GC pause", last.GCPause.String(), series(func(s metrics.Sample) float64 { return float64(s.GCPause) })) +
+		row("GC cycles", fmt.Sprint(last.NumGC), nil) +
+		"\n" + st.SectionTitle.Render(m.T("Rendering")) + "\n" +
+		row("Frame time", formatDuration(last.FrameTime), series(func(s metrics.Sample) float64 { return float64(s.FrameTime) })) +
+		row("Frames", fmt.Sprint(frames.Count), nil) +
+		row("Slowest", formatDuration(frames.Max), nil)
+
+	// Right column - Host and update latency
+	cpu := m.T("n/a")
+	if last.HostCPU >= 0 {
+		cpu = fmt.Sprintf("%.0f%%", last.HostCPU*100)
+	}
+	memory := m.T("n/a")
+	if last.HostMemTotal > 0 {
+		memory = fmt.Sprintf("%s / %s", formatBytes(last.HostMemUsed), formatBytes(last.HostMemTotal))
+	}
+	host := st.SectionTitle.Render(m.T("Host")) + "\n" +
+		row("CPU", cpu, series(func(s metrics.Sample) float64 { return max(s.HostCPU, 0) })) +
+		row("Memory", "", series(func(s metrics.Sample) float64 { return float64(s.HostMemUsed) })) +
+		fmt.Sprintf("%-12s %s\n", "", memory)
+
+	latency := st.SectionTitle.Render(m.T("Update Latency")) + "\n" +
+		fmt.Sprintf("%-24s %6s %9s %9s\n", m.T("MESSAGE"), m.T("COUNT"), m.T("MEAN"), m.T("MAX"))
+	for i, u := range m.Metrics().Updates() {
+		if i == 8 {
+			break
+		}
+		latency += fmt.Sprintf("%-24s %6d %9s %9s\n", truncate.StringWithTail(u.Kind, 24, "…"), u.Count, formatDuration(u.Mean()), formatDuration(u.Max))
+	}
+
+	left := st.Renderer().NewStyle().Width(width).Render(process)
+	right := st.Renderer().NewStyle().Width(width).Render(host + "\n" + latency)
+
+	k := m.Keys()
+	footer := m.T("sampling every %s • ", m.config.SampleInterval()) + m.ShortHelp(k.Faster, k.Slower, k.Reset, k.Back)
+	content := st.SectionTitle.Render(m.T("Performance Metrics")) + "\n" +
+		lipgloss.JoinHorizontal(lipgloss.Top, left, "    ", right)
+	if m.errorMsg != "" {
+		content += "\n" + st.ErrorText.Render(m.errorMsg)
+	}
+	return st.DocStyle.Render(content + "\n" + st.PageFooter.Render(footer))
+}
+
+// sparkline draws the last width values as block characters scaled
+// between their minimum and maximum
+func sparkline(values []float64, width int) string {
+	const bars = "▁▂▃▄▅▆▇█"
+	ticks := []rune(bars)
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(ticks)-1))
+		}
+		b.WriteRune(ticks[i])
+	}
+	return b.String()
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	}
+	return d.Round(time.Microsecond).String()
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"errors"
+	"mainframe/pkg/config"
+	"mainframe/pkg/presence"
+	"os/user"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// heartbeatMsg is sent every presence.Interval while presence is enabled
+type heartbeatMsg struct{}
+
+// presenceState is what the app keeps to publish heartbeats and show the
+// instructor's broadcasts
+type presenceState struct {
+	publisher *presence.Publisher
+	dir       string
+	user      string
+	lastInput time.Time
+	seen      string
+	broadcast *presence.Broadcast
+	err       error
+}
+
+// startPresence starts publishing heartbeats for name, or for the current
+// OS user if name is empty. Presence is off unless presence_dir is set.
+func (m *AppModel) startPresence(name string) error {
+	dir := m.config.PresenceDir
+	if dir == "" {
+		return nil
+	}
+	if name == "" {
+		name = currentUser()
+	}
+
+	p, err := presence.NewPublisher(dir, name)
+	if err != nil {
+		return err
+	}
+	m.presence = presenceState{
+		publisher: p,
+		dir:       dir,
+		user:      name,
+		lastInput: m.Now(),
+	}
+
+	// Only broadcasts sent after the session starts pop up
+	b, err := presence.Latest(dir, m.config.Instructor)
+	if b != nil {
+		m.presence.seen = b.ID
+	}
+	m.publishHeartbeat()
+	return errors.Join(m.presence.err, err)
+}
+
+func (m *AppModel) stopPresence() {
+	if m.presence.publisher != nil {
+		m.presence.publisher.Close()
+		m.presence.publisher = nil
+	}
+}
+
+func (m *AppModel) heartbeat() tea.Cmd {
+	if m.presence.publisher == nil {
+		return nil
+	}
+	return tea.Tick(presence.Interval, func(time.Time) tea.Msg {
+		return heartbeatMsg{}
+	})
+}
+
+// publishHeartbeat announces the current screen and picks up any new
+// broadcast from the instructor
+func (m *AppModel) publishHeartbeat() {
+	if m.presence.publisher == nil {
+		return
+	}
+	m.presence.err = m.presence.publisher.Publish(presence.Heartbeat{
+		User:      m.presence.user,
+		Screen:    screenName(m.screen),
+		Activity:  screenActivity(m.screen),
+		LastInput: m.presence.lastInput,
+	})
+
+	if !m.config.Enabled(config.FeaturePresenceBroadcasts, m.Now()) {
+		return
+	}
+	b, err := presence.Latest(m.presence.dir, m.config.Instructor)
+	if err != nil || b == nil || b.ID == m.presence.seen {
+		return
+	}
+	m.presence.seen = b.ID
+	m.presence.broadcast = b
+}
+
+func (m *AppModel) dismissBroadcast(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.Keys().Quit) {
+		return m, tea.Quit
+	}
+	m.presence.broadcast = nil
+	return m, nil
+}
+
+func (m *AppModel) broadcastView() string {
+	st := m.Styles()
+	b := m.presence.broadcast
+	return m.CenterView(
+		st.DialogBox.Render(
+			st.AppTitle.Render(m.T("Message From %s", b.From)) + "\n\n" +
+				st.Description.Render(b.Message) + "\n\n" +
+				st.PageFooter.Render(m.T("Sent %s • press any key to continue", b.Sent.Format("15:04"))),
+		),
+	)
+}
+
+// screenName is the name shown to the instructor for s
+func screenName(s tea.Model) string {
+	switch s.(type) {
+	case *HomeModel:
+		return "Home"
+	case *SettingsModel:
+		return "Settings"
+	case *DeveloperModel:
+		return "Developer Options"
+	case *FeaturesModel:
+		return "Feature Flags"
+	case *LocalModelModel:
+		return "Model Configuration"
+	case *LogViewerModel:
+		return "Logs"
+	case *PerformanceModel:
+		return "Performance Metrics"
+	case *NetworkModel:
+		return "Network Diagnostics"
+	case *InstructorModel:
+		return "Instructor"
+	}
+	return "Unknown"
+}
+
+// screenActivity describes what the user is doing on s, which for menus
+// is the highlighted option
+func screenActivity(s tea.Model) string {
+	switch s := s.(type) {
+	case *HomeModel:
+		return s.choices[s.cursor]
+	case *SettingsModel:
+		if s.showAPIInput {
+			return "Entering API key"
+		}
+		if s.showNewInput {
+			return "Creating profile"
+		}
+		return s.choices[s.cursor]
+	case *DeveloperModel:
+		return s.choices[s.cursor]
+	case *LocalModelModel:
+		if s.showInput {
+			return "Entering model path"
+		}
+		return s.choices[s.cursor]
+	}
+	return ""
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mainframe/pkg/config"
+	"mainframe/pkg/keys"
+	"mainframe/pkg/styles"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A recording is a JSON line per message. The first line has type
+// "session" and holds a sessionHeader. Messages whose type cannot be
+// replayed are kept with no payload so the recording still shows they
+// happened.
+type recordLine struct {
+	Time   time.Time       `json:"time"`
+	Width  int             `json:"width"`
+	Height int             `json:"height"`
+	Type   string          `json:"type"`
+	Msg    json.RawMessage `json:"msg,omitempty"`
+}
+
+const sessionType = "session"
+
+// sessionHeader is what a session started with that does not come from the
+// messages: the config, API keys removed, and what the machine it ran on
+// added to it, so the replay does not pick up the replaying machine's.
+type sessionHeader struct {
+	Config   json.RawMessage     `json:"config"`
+	Keys     map[string][]string `json:"keys"`
+	Language string              `json:"language"`
+	Themes   []styles.Theme      `json:"themes,omitempty"`
+	// Color and Background are the resolved color and background modes
+	Color      string `json:"color"`
+	Background string `json:"background"`
+}
+
+// msgCodec turns one message type into a recording payload and back
+type msgCodec struct {
+	encode func(tea.Msg) any
+	decode func(json.RawMessage) (tea.Msg, error)
+}
+
+// codecs are keyed by the %T name of the message
+var codecs = map[string]msgCodec{
+	"tea.KeyMsg": {
+		encode: func(msg tea.Msg) any { return tea.Key(msg.(tea.KeyMsg)) },
+		decode: func(data json.RawMessage) (tea.Msg, error) {
+			var k tea.Key
+			err := json.Unmarshal(data, &k)
+			return tea.KeyMsg(k), err
+		},
+	},
+	"tea.WindowSizeMsg": {
+		encode: func(msg tea.Msg) any { return msg },
+		decode: func(data json.RawMessage) (tea.Msg, error) {
+			var m tea.WindowSizeMsg
+			err := json.Unmarshal(data, &m)
+			return m, err
+		},
+	},
+	"tea.MouseMsg": {
+		encode: func(msg tea.Msg) any { return msg },
+		decode: func(data json.RawMessage) (tea.Msg, error) {
+			var m tea.MouseMsg
+			err := json.Unmarshal(data, &m)
+			return m, err
+		},
+	},
+	"ui.netdiagMsg": {
+		encode: func(msg tea.Msg) any { return msg.(netdiagMsg).report },
+		decode: func(data json.RawMessage) (tea.Msg, error) {
+			var m netdiagMsg
+			err := json.Unmarshal(data, &m.report)
+			return m, err
+		},
+	},
+	"ui.selfTestMsg": {
+		encode: func(msg tea.Msg) any { return msg.(selfTestMsg).report },
+		decode: func(data json.RawMessage) (tea.Msg, error) {
+			var m selfTestMsg
+			err := json.Unmarshal(data, &m.report)
+			for i, s := range m.report.Steps {
+				m.report.Steps[i].Duration = time.Duration(s.DurationMS * float64(time.Millisecond))
+			}
+			return m, err
+		},
+	},
+	"ui.configChangedMsg": empty(configChangedMsg{}),
+	"ui.heartbeatMsg":     empty(heartbeatMsg{}),
+	"ui.refreshMsg":       empty(refreshMsg{}),
+	"ui.sampleMsg":        empty(sampleMsg{}),
+	"ui.tailMsg":          empty(tailMsg{}),
+}
+
+func empty(msg tea.Msg) msgCodec {
+	return msgCodec{
+		encode: func(tea.Msg) any { return nil },
+		decode: func(json.RawMessage) (tea.Msg, error) { return msg, nil },
+	}
+}
+
+// recorder writes the messages of a session to a recording
+type recorder struct {
+	enc *json.Encoder
+}
+
+// newRecorder starts a recording of m, which must have its keys, language
+// and styles set up
+func newRecorder(w io.Writer, m *AppModel) (*recorder, error) {
+	snapshot := *m.config
+	snapshot.APIKeys = nil
+	cfg, err := json.Marshal(&snapshot)
+	if err != nil {
+		return nil, err
+	}
+	st := m.Styles()
+	data, err := json.Marshal(sessionHeader{
+		Config:     cfg,
+		Keys:       m.Keys().Bindings(),
+		Language:   m.Locale().Code(),
+		Themes:     st.CustomThemes(),
+		Color:      st.ColorMode(),
+		Background: st.BackgroundMode(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := &recorder{enc: json.NewEncoder(w)}
+	return r, r.enc.Encode(recordLine{Time: m.Now(), Type: sessionType, Msg: data})
+}
+
+// record appends msg to the recording. Keys typed into the API key field
+// are masked.
+func (r *recorder) record(m *AppModel, msg tea.Msg) error {
+	if r == nil {
+		return nil
+	}
+	if s, ok := m.screen.(*SettingsModel); ok && s.showAPIInput {
+		if k, ok := msg.(tea.KeyMsg); ok && k.Type == tea.KeyRunes {
+			k.Runes = []rune(strings.Repeat("*", len(k.Runes)))
+			msg = k
+		}
+	}
+
+	line := recordLine{Time: m.Now(), Width: m.width, Height: m.height, Type: fmt.Sprintf("%T", msg)}
+	if c, ok := codecs[line.Type]; ok {
+		if v := c.encode(msg); v != nil {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			line.Msg = data
+		}
+	}
+	return r.enc.Encode(line)
+}
+
+// Frame is the screen after one replayed message
+type Frame struct {
+	// Offset is how long into the session the message arrived
+	Offset time.Duration
+	Msg    string
+	View   string
+}
+
+// Replay feeds a recording into a new session drawn with renderer and
+// calls frame with the view after each message. Commands the session
+// returns are not run: their results are in the recording, so nothing
+// reaches the network, and the session's clock stands at the time of the
+// message being replayed. Settings saved during the replay go to a
+// scratch directory, and the keys, language, themes and colors are the
+// recorded ones rather than this machine's.
+func Replay(r io.Reader, renderer *lipgloss.Renderer, frame func(Frame) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var header recordLine
+	if !sc.Scan() {
+		return errors.Join(errors.New("recording is empty"), sc.Err())
+	}
+	if err := json.Unmarshal(sc.Bytes(), &header); err != nil || header.Type != sessionType {
+		return errors.New("not a mainframe recording: the first line is not a session header")
+	}
+
+	dir, err := os.MkdirTemp("", "mainframe-replay-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	cfg, err := replayConfig(dir, header.Msg)
+	if err != nil {
+		return err
+	}
+
+	now := header.Time
+	app := NewAppModel(cfg, AppOptions{
+		Renderer: renderer,
+		Clock:    func() time.Time { return now },
+	})
+	defer app.Close()
+	app.Init()
+
+	for n := 2; sc.Scan(); n++ {
+		var line recordLine
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		c, ok := codecs[line.Type]
+		if !ok {
+			continue
+		}
+		msg, err := c.decode(line.Msg)
+		if err != nil {
+			return fmt.Errorf("line %d: %s: %w", n, line.Type, err)
+		}
+
+		now = line.Time
+		app.Update(msg)
+		if err := frame(Frame{Offset: now.Sub(header.Time), Msg: describeMsg(msg), View: app.View()}); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// replayConfig loads the recorded config from dir so that saving it does
+// not touch the user's own file, with the recorded key bindings and themes
+// next to it. Nothing comes from the replaying machine's system config or
+// environment, and presence is left off so a replay never shows up as a
+// trainee.
+func replayConfig(dir string, header json.RawMessage) (*config.Config, error) {
+	var h sessionHeader
+	if err := json.Unmarshal(header, &h); err != nil {
+		return nil, fmt.Errorf("session header: %w", err)
+	}
+	if err := writeReplayFiles(dir, h); err != nil {
+		return nil, err
+	}
+	// Flags are not saved, so settings changed during the replay still
+	// land in the scratch file as they did in the session
+	flags := map[string]string{
+		"language":   h.Language,
+		"color":      h.Color,
+		"background": h.Background,
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(h.Config, &raw); err != nil {
+		return nil, fmt.Errorf("session header: %w", err)
+	}
+	delete(raw, "presence_dir")
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), data, 0600); err != nil {
+		return nil, err
+	}
+	return config.LoadIsolated(dir, flags)
+}
+
+// writeReplayFiles puts the recorded key bindings and themes where the
+// session loads them from
+func writeReplayFiles(dir string, h sessionHeader) error {
+	if err := keys.Save(filepath.Join(dir, "keys.toml"), h.Keys); err != nil {
+		return err
+	}
+	if len(h.Themes) == 0 {
+		return nil
+	}
+	themes := filepath.Join(dir, "themes")
+	if err := os.MkdirAll(themes, 0755); err != nil {
+		return err
+	}
+	for i, t := range h.Themes {
+		if err := styles.SaveTheme(filepath.Join(themes, fmt.Sprintf("theme-%d.toml", i)), t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,9 +2,11 @@ package ui
 
 import (
 	"mainframe/pkg/config"
-	"mainframe/pkg/styles"
+	"mainframe/pkg/i18n"
+	"mainframe/pkg/keys"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -15,6 +17,8 @@ type SettingsModel struct {
 	cursor       int
 	apiKeyInput  textinput.Model
 	showAPIInput bool
+	profileInput textinput.Model
+	showNewInput bool
 	modelChoice  string
 	config       *config.Config
 	quit         bool
@@ -22,31 +26,33 @@ type SettingsModel struct {
 	showHelp     bool
 }
 
-func NewSettingsModel() *SettingsModel {
+func NewSettingsModel(cfg *config.Config) *SettingsModel {
 	apiKey := textinput.New()
 	apiKey.Placeholder = "Enter your API key"
 	apiKey.Width = 50
 	apiKey.EchoMode = textinput.EchoPassword
 	apiKey.CharLimit = 100
 
-	cfg, err := config.Load()
-	if err != nil {
-		cfg = &config.Config{
-			AIModel: "local",
-		}
-	}
+	profileName := textinput.New()
+	profileName.Placeholder = "Enter a name for the new profile"
+	profileName.Width = 50
+	profileName.CharLimit = 32
 
 	return &SettingsModel{
 		choices: []string{
+			"Profile",
 			"AI Model",
 			"Model Configuration",
 			"Developer Options",
+			"Theme",
+			"Language",
 			"Back to Main Menu",
 		},
 		cursor:       0,
 		apiKeyInput:  apiKey,
 		showAPIInput: false,
-		modelChoice:  cfg.AIModel,
+		profileInput: profileName,
+		modelChoice:  cfg.Profile().AIModel,
 		config:       cfg,
 		quit:         false,
 	}
@@ -65,19 +71,17 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		k := m.Keys()
 		if m.showAPIInput {
 			m.apiKeyInput, cmd = m.apiKeyInput.Update(msg)
 
-			switch msg.String() {
-			case "enter":
-				if m.validateAPIKey() {
-					m.config.APIKey = m.apiKeyInput.Value()
+			switch {
+			case key.Matches(msg, k.Confirm):
+				if m.validateAPIKey() && m.saveAPIKey(m.apiKeyInput.Value()) {
 					m.showAPIInput = false
-					m.errorMsg = ""
-					config.Save(m.config)
 				}
 				return m, nil
-			case "esc":
+			case key.Matches(msg, k.Cancel):
 				m.showAPIInput = false
 				m.errorMsg = ""
 				return m, nil
@@ -85,92 +89,251 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
-		switch msg.String() {
-		case "ctrl+c", "q":
-			m.quit = true
-			return m, tea.Quit
-		case "up", "k":
-			if m.cursor > 0 {
-				m.cursor--
-			}
-		case "down", "j":
-			if m.cursor < len(m.choices)-1 {
-				m.cursor++
-			}
-		case "enter", " ":
-			switch m.cursor {
-			case 0: // AI Model
-				if m.modelChoice == "local" {
-					m.modelChoice = "gpt"
-				} else {
-					m.modelChoice = "local"
+		if m.showNewInput {
+			m.profileInput, cmd = m.profileInput.Update(msg)
+
+			switch {
+			case key.Matches(msg, k.Confirm):
+				name := strings.TrimSpace(m.profileInput.Value())
+				if err := m.config.AddProfile(name); err != nil {
+					m.errorMsg = err.Error()
+					return m, nil
 				}
-				m.config.AIModel = m.modelChoice
-				config.Save(m.config)
-			case 1: // Model Configuration
-				if m.modelChoice == "local" {
-					return NewLocalModelModel(m.config), nil
-				} else {
-					m.showAPIInput = true
-					m.apiKeyInput.Focus()
-					return m, textinput.Blink
+				if !m.switchProfile(name) {
+					delete(m.config.Profiles, name)
+					return m, nil
 				}
-			case 2: // Developer Options
-				return NewDeveloperModel(m.config), nil
-			case 3: // Back to Main Menu
-				return NewHomeModel(), nil
+				m.showNewInput = false
+				return m, nil
+			case key.Matches(msg, k.Cancel):
+				m.showNewInput = false
+				m.errorMsg = ""
+				return m, nil
 			}
-		case "?":
+			return m, cmd
+		}
+
+		if !m.showHelp && m.ContentKey(msg) {
+			return m, nil
+		}
+		switch {
+		case key.Matches(msg, k.Quit):
+			m.quit = true
+			return m, tea.Quit
+		case key.Matches(msg, k.Up):
+			m.MoveCursor(&m.cursor, -1, len(m.choices))
+		case key.Matches(msg, k.Down):
+			m.MoveCursor(&m.cursor, 1, len(m.choices))
+		case key.Matches(msg, k.Select):
+			return m.choose()
+		case key.Matches(msg, k.NewProfile):
+			if m.cursor == 0 {
+				m.showNewInput = true
+				m.profileInput.SetValue("")
+				m.profileInput.Focus()
+				return m, textinput.Blink
+			}
+		case key.Matches(msg, k.Help):
 			m.showHelp = !m.showHelp
-		case "esc":
-			return NewHomeModel(), nil
+		case key.Matches(msg, k.Back):
+			return NewHomeModel(m.config), nil
+		}
+
+	case tea.MouseMsg:
+		if m.showHelp || m.showAPIInput || m.showNewInput {
+			return m, nil
+		}
+		if m.MenuMouse(msg, &m.cursor, len(m.choices)) {
+			return m.choose()
 		}
 	}
 
 	return m, nil
 }
 
+// choose changes or opens the setting under the cursor
+func (m *SettingsModel) choose() (tea.Model, tea.Cmd) {
+	switch m.cursor {
+	case 0: // Profile
+		names := m.config.ProfileNames()
+		for i, name := range names {
+			if name == m.config.ActiveProfile {
+				m.switchProfile(names[(i+1)%len(names)])
+				break
+			}
+		}
+	case 1: // AI Model
+		old := m.modelChoice
+		if m.modelChoice == "local" {
+			m.modelChoice = "gpt"
+		} else {
+			m.modelChoice = "local"
+		}
+		m.config.Profile().AIModel = m.modelChoice
+		m.save(func() {
+			m.modelChoice = old
+			m.config.Profile().AIModel = old
+		})
+	case 2: // Model Configuration
+		if m.modelChoice == "local" {
+			return NewLocalModelModel(m.config), nil
+		} else {
+			m.showAPIInput = true
+			m.apiKeyInput.Focus()
+			return m, textinput.Blink
+		}
+	case 3: // Developer Options
+		return NewDeveloperModel(m.config), nil
+	case 4: // Theme
+		st := m.Styles()
+		names := st.Themes()
+		next := names[0]
+		for i, name := range names {
+			if name == st.Theme().Name {
+				next = names[(i+1)%len(names)]
+				break
+			}
+		}
+		old, oldName := m.config.Theme, st.Theme().Name
+		if err := st.SetTheme(next); err == nil {
+			m.config.Theme = next
+			m.save(func() {
+				m.config.Theme = old
+				st.SetTheme(oldName)
+			})
+		}
+	case 5: // Language
+		codes := append([]string{i18n.Auto}, i18n.Languages()...)
+		current := m.config.Language
+		if current == "" {
+			current = i18n.Auto
+		}
+		next := codes[0]
+		for i, code := range codes {
+			if code == current {
+				next = codes[(i+1)%len(codes)]
+				break
+			}
+		}
+		old := m.config.Language
+		m.config.Language = next
+		m.Locale().Set(next)
+		m.save(func() {
+			m.config.Language = old
+			m.Locale().Set(current)
+		})
+	case 6: // Back to Main Menu
+		return NewHomeModel(m.config), nil
+	}
+	return m, nil
+}
+
+// switchProfile makes name the active profile, reporting whether it was
+// saved
+func (m *SettingsModel) switchProfile(name string) bool {
+	old := m.config.ActiveProfile
+	if err := m.config.UseProfile(name); err != nil {
+		m.errorMsg = err.Error()
+		return false
+	}
+	m.modelChoice = m.config.Profile().AIModel
+	return m.save(func() {
+		m.config.ActiveProfile = old
+		m.modelChoice = m.config.Profile().AIModel
+	})
+}
+
+// saveAPIKey stores key for the active profile, reporting whether it was
+// saved
+func (m *SettingsModel) saveAPIKey(key string) bool {
+	p := m.config.Profile()
+	ref := p.APIKeyRef
+	oldKey, had := m.config.APIKeys[m.config.ActiveProfile]
+	m.config.SetAPIKey(key)
+	return m.save(func() {
+		p.APIKeyRef = ref
+		if had {
+			m.config.APIKeys[m.config.ActiveProfile] = oldKey
+		} else {
+			delete(m.config.APIKeys, m.config.ActiveProfile)
+		}
+	})
+}
+
+// save writes the config. If that fails undo puts back what was changed,
+// so the settings shown are always the ones on disk.
+func (m *SettingsModel) save(undo func()) bool {
+	m.errorMsg = ""
+	if err := config.Save(m.config); err != nil {
+		undo()
+		m.errorMsg = m.T("Error saving config: %v", err)
+		return false
+	}
+	return true
+}
+
 func (m *SettingsModel) validateAPIKey() bool {
 	key := m.apiKeyInput.Value()
 	if len(key) < 32 {
-		m.errorMsg = "API key must be at least 32 characters"
+		m.errorMsg = m.T("API key must be at least 32 characters")
 		return false
 	}
 	if !strings.HasPrefix(key, "sk-") {
-		m.errorMsg = "Invalid API key format"
+		m.errorMsg = m.T("Invalid API key format")
 		return false
 	}
 	return true
 }
 
 func (m *SettingsModel) View() string {
+	st := m.Styles()
+	k := m.Keys()
 	if m.showHelp {
 		return m.CenterView(
-			styles.DialogBox.Render(
-				styles.AppTitle.Render("Settings Help") + "\n\n" +
-					"Navigation:\n" +
-					"• Up/Down or j/k: Move cursor\n" +
-					"• Enter/Space: Select option\n" +
-					"• ?: Toggle help\n" +
-					"• Esc: Back to main menu\n\n" +
-					styles.PageFooter.Render("Press ? to close help"),
+			st.DialogBox.Render(
+				st.AppTitle.Render(m.T("Settings Help")) + "\n\n" +
+					m.FullHelp(
+						[]key.Binding{k.Up, k.Down, k.Select, k.Palette},
+						[]key.Binding{keys.Describe(k.NewProfile, "new profile (on Profile)"), k.Help, keys.Describe(k.Back, "back to main menu")},
+						[]key.Binding{k.Focus, k.PageUp, k.PageDown},
+					) + "\n\n" +
+					st.PageFooter.Render(m.ShortHelp(keys.Describe(k.Help, "close help"))),
 			),
 		)
 	}
 
 	if m.showAPIInput {
+		m.apiKeyInput.Placeholder = m.T("Enter your API key")
+		return m.CenterView(
+			st.DialogBox.Render(
+				st.AppTitle.Render(m.T("API Key Configuration")) + "\n\n" +
+					st.MenuOption.Render(m.T("Enter your OpenAI API key:")) + "\n" +
+					st.InputBox.Render(m.apiKeyInput.View()) + "\n" +
+					(func() string {
+						if m.errorMsg != "" {
+							return "\n" + st.ErrorText.Render(m.errorMsg)
+						}
+						return ""
+					})() + "\n\n" +
+					st.PageFooter.Render(m.ShortHelp(k.Confirm, k.Cancel)),
+			),
+		)
+	}
+
+	if m.showNewInput {
+		m.profileInput.Placeholder = m.T("Enter a name for the new profile")
 		return m.CenterView(
-			styles.DialogBox.Render(
-				styles.AppTitle.Render("API Key Configuration") + "\n\n" +
-					styles.MenuOption.Render("Enter your OpenAI API key:") + "\n" +
-					styles.InputBox.Render(m.apiKeyInput.View()) + "\n" +
+			st.DialogBox.Render(
+				st.AppTitle.Render(m.T("New Profile")) + "\n\n" +
+					st.MenuOption.Render(m.T("The new profile starts as a copy of %q:", m.config.ActiveProfile)) + "\n" +
+					st.InputBox.Render(m.profileInput.View()) + "\n" +
 					(func() string {
 						if m.errorMsg != "" {
-							return "\n" + styles.ErrorText.Render(m.errorMsg)
+							return "\n" + st.ErrorText.Render(m.errorMsg)
 						}
 						return ""
 					})() + "\n\n" +
-					styles.PageFooter.Render("enter to save • esc to cancel"),
+					st.PageFooter.Render(m.ShortHelp(keys.Describe(k.Confirm, "create"), k.Cancel)),
 			),
 		)
 	}
@@ -178,31 +341,41 @@ func (m *SettingsModel) View() string {
 	// Left panel - Menu options
 	var menuContent string
 	for i, choice := range m.choices {
-		cursor := "  "
-		if m.cursor == i {
-			cursor = "> "
-		}
-
-		if m.cursor == i {
-			menuContent += styles.HighlightedOption.Render(cursor+choice) + "\n"
-		} else {
-			menuContent += styles.MenuOption.Render(cursor+choice) + "\n"
-		}
+		menuContent += m.MenuOption(i, m.cursor, m.T(choice)) + "\n"
 	}
 
-	menuView := styles.MenuBox.Render(
-		styles.SectionTitle.Render("Settings") + "\n\n" +
-			menuContent + "\n\n" +
-			styles.PageFooter.Render("? for help • esc to go back"),
+	menuView := st.MenuBox.Render(
+		st.SectionTitle.Render(m.T("Settings")) + "\n\n" +
+			menuContent + "\n" +
+			st.MenuFooter.Render(m.ShortHelp(k.Help, k.Back)),
 	)
 
 	// Right panel - Detailed content
 	var detailContent string
 	switch m.cursor {
-	case 0: // AI Model
-		detailContent = styles.MainTitle.Render("AI Model Selection") + "\n\n" +
-			styles.Description.Render(
-				"Choose the AI model that powers your learning experience:\n\n"+
+	case 0: // Profile
+		var profileList string
+		for _, name := range m.config.ProfileNames() {
+			marker := "  "
+			if name == m.config.ActiveProfile {
+				marker = "▸ "
+			}
+			profileList += marker + name + " (" + m.config.Profiles[name].AIModel + ")\n"
+		}
+		detailContent = st.MainTitle.Render(m.T("Profiles")) + "\n\n" +
+			st.Description.Render(
+				m.T("Each profile keeps its own model, API key and developer settings.\n"+
+					"Switch profiles to move between setups without re-entering keys.")+"\n\n"+
+					profileList+"\n"+
+					m.T("Press %s to switch to the next profile\n"+
+						"Press %s to create a new profile from the current one", k.Select.Help().Key, k.NewProfile.Help().Key),
+			) +
+			st.StatusIndicator.Render(m.T("Active Profile: %s", m.config.ActiveProfile))
+
+	case 1: // AI Model
+		detailContent = st.MainTitle.Render(m.T("AI Model Selection")) + "\n\n" +
+			st.Description.Render(
+				m.T("Choose the AI model that powers your learning experience:\n\n"+
 					"• Local Model\n"+
 					"  Run models directly on your machine\n"+
 					"  Supports Llama 2, GPT-J, and BLOOM\n"+
@@ -210,44 +383,91 @@ func (m *SettingsModel) View() string {
 					"• GPT Model\n"+
 					"  Use OpenAI's powerful GPT models\n"+
 					"  Requires internet and API key\n"+
-					"  State-of-the-art performance\n\n",
+					"  State-of-the-art performance")+"\n\n",
 			) +
-			styles.StatusIndicator.Render("Current Model: "+strings.ToUpper(m.modelChoice))
+			st.StatusIndicator.Render(m.T("Current Model: %s", strings.ToUpper(m.modelChoice)))
 
-	case 1: // Model Configuration
+	case 2: // Model Configuration
 		if m.modelChoice == "local" {
-			detailContent = styles.MainTitle.Render("Local Model Setup") + "\n\n" +
-				styles.Description.Render(
-					"Configure your local model installation:\n\n"+
+			detailContent = st.MainTitle.Render(m.T("Local Model Setup")) + "\n\n" +
+				st.Description.Render(
+					m.T("Configure your local model installation:\n\n"+
 						"1. Download a compatible model\n"+
 						"2. Set up the model path\n"+
 						"3. Test the connection\n\n"+
-						"Press ENTER to start the setup process",
+						"Press %s to start the setup process", k.Select.Help().Key),
 				)
 		} else {
-			detailContent = styles.MainTitle.Render("OpenAI Configuration") + "\n\n" +
-				styles.Description.Render(
-					"Configure your OpenAI API access:\n\n"+
+			detailContent = st.MainTitle.Render(m.T("OpenAI Configuration")) + "\n\n" +
+				st.Description.Render(
+					m.T("Configure your OpenAI API access:\n\n"+
 						"• Set up your API key\n"+
 						"• Manage model preferences\n"+
 						"• Test API connectivity\n\n"+
-						"Press ENTER to configure your API key",
+						"Press %s to configure your API key", k.Select.Help().Key),
 				)
 		}
 
-	case 2: // Developer Options
-		detailContent = styles.MainTitle.Render("Developer Options") + "\n\n" +
-			styles.Description.Render(
-				"Advanced settings for development and debugging:\n\n"+
+	case 3: // Developer Options
+		detailContent = st.MainTitle.Render(m.T("Developer Options")) + "\n\n" +
+			st.Description.Render(
+				m.T("Advanced settings for development and debugging:\n\n"+
 					"• Debug logging\n"+
 					"• Performance monitoring\n"+
 					"• Experimental features\n"+
 					"• Network diagnostics\n\n"+
-					"Press ENTER to access developer settings",
+					"Press %s to access developer settings", k.Select.Help().Key),
 			)
+
+	case 4: // Theme
+		var themeList string
+		for _, name := range st.Themes() {
+			marker := "  "
+			if name == st.Theme().Name {
+				marker = "▸ "
+			}
+			themeList += marker + name + "\n"
+		}
+		detailContent = st.MainTitle.Render(m.T("Color Theme")) + "\n\n" +
+			st.Description.Render(
+				m.T("Change the colors used throughout Mainframe:")+"\n\n"+
+					themeList+"\n"+
+					m.T("Custom themes are loaded from %s/*.toml", m.config.ThemesDir())+"\n\n"+
+					m.T("Press %s to switch to the next theme", k.Select.Help().Key),
+			) +
+			st.StatusIndicator.Render(m.T("Current Theme: %s", st.Theme().Name))
+
+	case 5: // Language
+		var languageList string
+		current := m.config.Language
+		if current == "" {
+			current = i18n.Auto
+		}
+		for _, code := range append([]string{i18n.Auto}, i18n.Languages()...) {
+			marker := "  "
+			if code == current {
+				marker = "▸ "
+			}
+			name := i18n.Name(code)
+			if code == i18n.Auto {
+				name = m.T("Automatic (%s)", i18n.Name(i18n.Detect()))
+			}
+			languageList += marker + name + "\n"
+		}
+		detailContent = st.MainTitle.Render(m.T("Language")) + "\n\n" +
+			st.Description.Render(
+				m.T("Change the language of Mainframe. Automatic follows\n"+
+					"your system locale from LC_ALL, LC_MESSAGES or LANG.")+"\n\n"+
+					languageList+"\n"+
+					m.T("Press %s to switch to the next language", k.Select.Help().Key),
+			) +
+			st.StatusIndicator.Render(m.T("Current Language: %s", i18n.Name(m.Locale().Code())))
+	}
+	if m.errorMsg != "" {
+		detailContent += "\n\n" + st.ErrorText.Render(m.errorMsg)
 	}
 
-	detailView := styles.ContentBox.Render(detailContent)
+	detailView := m.ContentView(detailContent)
 
 	// Combine views
 	return m.SplitView(menuView, detailView)
@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+)
+
+// Zone names shared by the split view screens
+const (
+	zoneMenu    = "menu"
+	zoneContent = "content"
+)
+
+// optionZone names the zone of the i-th menu option
+func optionZone(i int) string {
+	return fmt.Sprintf("option:%d", i)
+}
+
+// Rect is an area of the screen in cells
+type Rect struct {
+	X, Y, Width, Height int
+}
+
+// Contains reports whether the cell at x, y is inside r
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// zones tells the mouse handling where parts of the last frame were drawn.
+// Views wrap the parts that react to the mouse in marks, zero width escape
+// sequences that survive lipgloss layout, and the app scans the finished
+// frame for them before it is printed. Screens cannot work the positions
+// out themselves because boxes are padded and centered after they render.
+//
+// Every line of a zone is marked on its own, so a zone must not be
+// wrapped again after it is marked.
+type zones struct {
+	ids   []string
+	rects map[string]Rect
+}
+
+func newZones() *zones {
+	return &zones{rects: map[string]Rect{}}
+}
+
+// mark wraps each line of s in the marks of zone id
+func (z *zones) mark(id, s string) string {
+	n := len(z.ids)
+	z.ids = append(z.ids, id)
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = fmt.Sprintf("\x1b[%dz%s\x1b[%d;1z", n, l, n)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// get returns where zone id was drawn in the last frame
+func (z *zones) get(id string) (Rect, bool) {
+	r, ok := z.rects[id]
+	return r, ok
+}
+
+// scan records where each marked zone ended up in view and returns view
+// without the marks. Zones marked for this frame start over afterwards.
+func (z *zones) scan(view string) string {
+	rects := map[string]Rect{}
+	add := func(id string, line Rect) {
+		r, ok := rects[id]
+		if !ok {
+			rects[id] = line
+			return
+		}
+		x, y := min(r.X, line.X), min(r.Y, line.Y)
+		right := max(r.X+r.Width, line.X+line.Width)
+		bottom := max(r.Y+r.Height, line.Y+line.Height)
+		rects[id] = Rect{X: x, Y: y, Width: right - x, Height: bottom - y}
+	}
+
+	var out strings.Builder
+	out.Grow(len(view))
+	opened := map[int]int{}
+	x, y := 0, 0
+	for i := 0; i < len(view); {
+		c := view[i]
+		switch {
+		case c == '\n':
+			// A zone wrapped after it was marked ends with its line
+			for n, start := range opened {
+				add(z.ids[n], Rect{X: start, Y: y, Width: x - start, Height: 1})
+			}
+			clear(opened)
+			x = 0
+			y++
+			out.WriteByte(c)
+			i++
+		case c == '\x1b':
+			end := escapeEnd(view, i)
+			seq := view[i:end]
+			if n, closing, ok := parseMark(seq); ok && n < len(z.ids) {
+				if !closing {
+					opened[n] = x
+				} else if start, ok := opened[n]; ok {
+					add(z.ids[n], Rect{X: start, Y: y, Width: x - start, Height: 1})
+					delete(opened, n)
+				}
+			} else {
+				out.WriteString(seq)
+			}
+			i = end
+		default:
+			r, size := utf8.DecodeRuneInString(view[i:])
+			x += runewidth.RuneWidth(r)
+			out.WriteString(view[i : i+size])
+			i += size
+		}
+	}
+
+	z.rects = rects
+	z.ids = z.ids[:0]
+	return out.String()
+}
+
+// escapeEnd returns the index just past the escape sequence starting at i
+func escapeEnd(s string, i int) int {
+	j := i + 1
+	if j < len(s) && s[j] == '[' {
+		j++
+		for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+			j++
+		}
+	}
+	return min(j+1, len(s))
+}
+
+// parseMark reads a mark made by zones.mark
+func parseMark(seq string) (n int, closing, ok bool) {
+	if !strings.HasPrefix(seq, "\x1b[") || !strings.HasSuffix(seq, "z") {
+		return 0, false, false
+	}
+	body := seq[2 : len(seq)-1]
+	body, closing = strings.CutSuffix(body, ";1")
+	n, err := strconv.Atoi(body)
+	return n, closing, err == nil
+}
+
+// Mark tags s as zone id so mouse handling can find it with Zone
+func (m *BaseModel) Mark(id, s string) string {
+	if m.zones == nil {
+		return s
+	}
+	return m.zones.mark(id, s)
+}
+
+// Zone returns where zone id was drawn in the last frame
+func (m *BaseModel) Zone(id string) (Rect, bool) {
+	if m.zones == nil {
+		return Rect{}, false
+	}
+	return m.zones.get(id)
+}
+
+// InZone reports whether the mouse event happened inside zone id
+func (m *BaseModel) InZone(id string, msg tea.MouseMsg) bool {
+	r, ok := m.Zone(id)
+	return ok && r.Contains(msg.X, msg.Y)
+}
+
+// wheelLines is how far one step of the mouse wheel scrolls
+const wheelLines = 3
+
+// MenuMouse handles the mouse for a menu of n options. The wheel moves the
+// cursor, or scrolls the content box when it is over it, and a click moves
+// the cursor to the option clicked. It reports whether an option was
+// clicked, which the screen then selects.
+func (m *BaseModel) MenuMouse(msg tea.MouseMsg, cursor *int, n int) bool {
+	switch msg.Type {
+	case tea.MouseWheelUp, tea.MouseWheelDown:
+		delta := 1
+		if msg.Type == tea.MouseWheelUp {
+			delta = -1
+		}
+		if m.InZone(zoneContent, msg) {
+			m.content.scroll(delta * wheelLines)
+		} else {
+			m.MoveCursor(cursor, delta, n)
+		}
+	case tea.MouseLeft:
+		for i := 0; i < n; i++ {
+			if m.InZone(optionZone(i), msg) {
+				m.MoveCursor(cursor, i-*cursor, n)
+				return true
+			}
+		}
+	}
+	return false
+}
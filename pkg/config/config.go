@@ -2,65 +2,361 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
+// CurrentSchemaVersion is the config layout written by this build
+const CurrentSchemaVersion = 2
+
+// ErrCorrupt is returned when config.json could not be parsed. The broken
+// file has been moved aside and replaced with defaults.
+var ErrCorrupt = errors.New("config file is corrupt")
+
+// ErrReadOnly is returned by Save when the user file could not be read,
+// for example because a newer version wrote it. Writing it would lose
+// whatever this build did not understand.
+var ErrReadOnly = errors.New("config file is read-only")
+
 type Config struct {
-	AIModel      string `json:"ai_model"`
-	APIKey       string `json:"api_key"`
-	Debug        bool   `json:"debug"`
-	Logs         bool   `json:"logs"`
-	Experimental bool   `json:"experimental"`
-}
-
-var (
-	defaultConfig = Config{
-		AIModel:      "local",
-		APIKey:       "",
-		Debug:        false,
-		Logs:         false,
-		Experimental: false,
-	}
-	configDir  string
-	configPath string
-)
+	SchemaVersion int                 `json:"schema_version"`
+	ActiveProfile string              `json:"active_profile"`
+	Profiles      map[string]*Profile `json:"profiles"`
+	APIKeys       map[string]string   `json:"api_keys,omitempty"`
+	Theme         string              `json:"theme,omitempty"`
+	Color         string              `json:"color,omitempty"`
+	Background    string              `json:"background,omitempty"`
+	// Accessible renders the TUI as linear text for screen readers
+	Accessible bool `json:"accessible,omitempty"`
+	// Language is the code of the TUI's language, or auto to follow the
+	// locale
+	Language    string `json:"language,omitempty"`
+	PresenceDir string `json:"presence_dir,omitempty"`
+	// Instructor is the login name whose broadcasts are shown
+	Instructor string `json:"instructor,omitempty"`
+	// MetricsInterval is how often the Performance Metrics panel samples,
+	// as a duration such as "500ms"
+	MetricsInterval string `json:"metrics_interval,omitempty"`
+
+	origin *origin
+}
+
+var defaultProfile = Profile{
+	AIModel:      "local",
+	Debug:        false,
+	Logs:         false,
+	Experimental: false,
+}
+
+// Default returns a fresh copy of the built-in configuration
+func Default() *Config {
+	p := defaultProfile
+	return &Config{
+		SchemaVersion: CurrentSchemaVersion,
+		ActiveProfile: DefaultProfile,
+		Profiles:      map[string]*Profile{DefaultProfile: &p},
+	}
+}
+
+// Load assembles the effective configuration from, in order, the built-in
+// defaults, SystemPath, the user file at Path, MAINFRAME_* environment
+// variables and flags, which maps config keys to command-line values.
+// A problem in one layer is reported but does not stop the others from
+// being applied.
+func Load(flags map[string]string) (*Config, error) {
+	return LoadFrom(Dir(), flags)
+}
+
+// LoadFrom is Load with the user file kept in dir instead of Dir. The
+// returned Config saves back to the same place.
+func LoadFrom(dir string, flags map[string]string) (*Config, error) {
+	path := filepath.Join(dir, "config.json")
+	user, err := loadUser(path)
+	cfg, layerErr := assemble(path, user, flags, false)
+	if user == nil && err != nil {
+		cfg.origin.readOnly = err
+	}
+	return cfg, errors.Join(err, layerErr)
+}
+
+// LoadIsolated is LoadFrom without anything from the machine it runs on:
+// no SystemPath, no MAINFRAME_* variables and env: key references that
+// resolve to no key. Only the defaults, the user file in dir and flags
+// count.
+func LoadIsolated(dir string, flags map[string]string) (*Config, error) {
+	path := filepath.Join(dir, "config.json")
+	user, err := loadUser(path)
+	cfg, layerErr := assemble(path, user, flags, true)
+	if user == nil && err != nil {
+		cfg.origin.readOnly = err
+	}
+	return cfg, errors.Join(err, layerErr)
+}
+
+// File returns the user file the config is saved to
+func (c *Config) File() string {
+	if c.origin == nil {
+		return Path()
+	}
+	return c.origin.path
+}
+
+// assemble builds the effective configuration around an already loaded
+// user layer read from path. An isolated config leaves out the system file
+// and the environment.
+func assemble(path string, user map[string]any, flags map[string]string, isolated bool) (*Config, error) {
+	o := &origin{
+		path:      path,
+		sources:   map[string]Layer{},
+		overrides: map[string]Layer{},
+		user:      map[string]any{},
+		disk:      flatten(user),
+		flags:     flags,
+		isolated:  isolated,
+	}
+	merged := toRaw(Default())
+	var errs []error
 
-func init() {
-	homeDir, err := os.UserHomeDir()
+	if !isolated {
+		if raw, err := readLayer(SystemPath); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", SystemPath, err))
+		} else {
+			mergeRaw(merged, raw, "", LayerSystem, o.sources)
+		}
+	}
+	if user != nil {
+		o.user = user
+		mergeRaw(merged, user, "", LayerUser, o.sources)
+	}
+
+	cfg := &Config{}
+	if err := fromRaw(merged, cfg); err != nil {
+		errs = append(errs, err)
+		cfg = Default()
+	}
+	cfg.origin = o
+	cfg.normalize()
+
+	for _, f := range fields {
+		v, ok := os.LookupEnv(f.Env)
+		if !ok || isolated {
+			continue
+		}
+		if err := f.set(cfg, v); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.Env, err))
+			continue
+		}
+		o.overrides[f.Key] = LayerEnv
+	}
+
+	for key := range flags {
+		if _, err := lookupField(key); err != nil {
+			errs = append(errs, fmt.Errorf("--%s: %w", flagName(key), err))
+		}
+	}
+	for _, f := range fields {
+		v, ok := flags[f.Key]
+		if !ok {
+			continue
+		}
+		if err := f.set(cfg, v); err != nil {
+			errs = append(errs, fmt.Errorf("--%s: %w", f.Flag, err))
+			continue
+		}
+		o.overrides[f.Key] = LayerFlag
+	}
+
+	o.loaded = flatten(toRaw(cfg))
+	return cfg, errors.Join(errs...)
+}
+
+// Save writes every setting changed since Load to the user config file.
+// Values that came from the system file, environment or flags and were
+// not changed are left out so they keep following their own layer. If the
+// file was edited by someone else in the meantime nothing is written and
+// ErrConflict is returned. A user file that cannot be read is never
+// overwritten, Save returns ErrReadOnly instead.
+func Save(config *Config) error {
+	config.SchemaVersion = CurrentSchemaVersion
+	if config.origin == nil {
+		return writeUser(Path(), toRaw(config))
+	}
+
+	o := config.origin
+	if o.readOnly != nil {
+		return fmt.Errorf("%w: %v", ErrReadOnly, o.readOnly)
+	}
+	disk, err := readLayer(o.path)
 	if err != nil {
-		homeDir = "."
+		return fmt.Errorf("%w: %v", ErrReadOnly, err)
+	}
+	if !sameFlat(flatten(disk), o.disk) {
+		return ErrConflict
+	}
+
+	current := flatten(toRaw(config))
+	for path, v := range current {
+		if old, ok := o.loaded[path]; ok && old == v {
+			continue
+		}
+		setPath(o.user, path, v)
+		o.sources[path] = LayerUser
+	}
+	for path := range o.loaded {
+		if _, ok := current[path]; !ok {
+			deletePath(o.user, path)
+		}
+	}
+	o.loaded = current
+	if err := writeUser(o.path, o.user); err != nil {
+		return err
 	}
-	configDir = filepath.Join(homeDir, ".mainframe")
-	configPath = filepath.Join(configDir, "config.json")
+	o.disk = flatten(o.user)
+	return nil
 }
 
-func Load() (*Config, error) {
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return &defaultConfig, err
+// normalize fills in anything a partial config document left out
+func (c *Config) normalize() {
+	c.SchemaVersion = CurrentSchemaVersion
+	c.Profile()
+	for _, p := range c.Profiles {
+		if p.AIModel == "" {
+			p.AIModel = defaultProfile.AIModel
+		}
+	}
+}
+
+// readLayer reads and migrates a config file. A missing file is an empty
+// layer.
+func readLayer(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	raw, _, err := decode(data)
+	if err == nil {
+		err = fromRaw(raw, &Config{})
 	}
+	return raw, err
+}
 
-	data, err := os.ReadFile(configPath)
+// loadUser reads the user file, creating it on first run, backing it up if
+// it is corrupt or holds values of the wrong type, and writing it back if
+// it needed migrating or repairing
+func loadUser(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &defaultConfig, Save(&defaultConfig)
+			raw := map[string]any{"schema_version": CurrentSchemaVersion}
+			return raw, writeUser(path, raw)
 		}
-		return &defaultConfig, err
+		return nil, err
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
-		return &defaultConfig, err
+	raw, migrated, err := decode(data)
+	if errors.Is(err, ErrCorrupt) {
+		raw = map[string]any{"schema_version": CurrentSchemaVersion}
+		return raw, recoverCorrupt(path, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bad := wrongTypes(raw); len(bad) > 0 {
+		return raw, repairTypes(path, raw, bad)
+	}
+	if err := fromRaw(raw, &Config{}); err != nil {
+		return nil, err
 	}
 
-	return &config, nil
+	if migrated {
+		return raw, writeUser(path, raw)
+	}
+	return raw, nil
 }
 
-func Save(config *Config) error {
-	data, err := json.MarshalIndent(config, "", "  ")
+// decode parses a config file, running any pending migrations. The
+// returned bool reports whether the document was upgraded.
+func decode(data []byte) (map[string]any, bool, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	if raw == nil {
+		raw = map[string]any{}
+	}
+
+	from := schemaVersion(raw)
+	if from > CurrentSchemaVersion {
+		return nil, false, fmt.Errorf("config schema version %d is newer than supported version %d", from, CurrentSchemaVersion)
+	}
+	if err := migrate(raw, from); err != nil {
+		return nil, false, err
+	}
+
+	return raw, from != CurrentSchemaVersion, nil
+}
+
+func writeUser(path string, raw map[string]any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	raw["schema_version"] = CurrentSchemaVersion
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// recoverCorrupt moves an unreadable config.json aside and starts a fresh
+// one in its place
+func recoverCorrupt(path string, cause error) error {
+	backup, err := backupConfig(path)
 	if err != nil {
+		return fmt.Errorf("%v (backup failed: %v)", cause, err)
+	}
+	if err := writeUser(path, map[string]any{}); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w, moved to %s", cause, backup)
+}
+
+// repairTypes removes the keys holding a value of the wrong type from raw
+// and writes what is left back to path, keeping the original next to it
+func repairTypes(path string, raw map[string]any, bad []invalidKey) error {
+	var errs []error
+	for _, b := range bad {
+		deletePath(raw, b.path)
+		errs = append(errs, fmt.Errorf("%s: %s, removed so the default applies", b.path, b.reason))
+	}
+	backup, err := backupConfig(path)
+	if err != nil {
+		return fmt.Errorf("%w (backup failed: %v)", errors.Join(errs...), err)
+	}
+	if err := writeUser(path, raw); err != nil {
 		return err
 	}
+	return fmt.Errorf("%w\nthe original is in %s", errors.Join(errs...), backup)
+}
 
-	return os.WriteFile(configPath, data, 0644)
+// backupConfig moves path aside to a name of its own, so backups made in
+// the same second do not overwrite each other
+func backupConfig(path string) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".bak-"+time.Now().Format("20060102-150405")+"-*")
+	if err != nil {
+		return "", err
+	}
+	backup := f.Name()
+	f.Close()
+	if err := os.Rename(path, backup); err != nil {
+		os.Remove(backup)
+		return "", err
+	}
+	return backup, nil
 }
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBackupsInTheSameSecondAreKept(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	var backups []string
+	for _, data := range []string{"first", "second"} {
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		backup, err := backupConfig(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		backups = append(backups, backup)
+	}
+	if backups[0] == backups[1] {
+		t.Fatalf("both backups are named %s", backups[0])
+	}
+	for i, want := range []string{"first", "second"} {
+		if data, err := os.ReadFile(backups[i]); err != nil || string(data) != want {
+			t.Errorf("%s holds %q (%v), want %q", backups[i], data, err, want)
+		}
+	}
+}
+
+func TestWrongTypeOnlyDropsThatKey(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	doc := `{"schema_version": 2, "theme": "amber", "profiles": {"default": {"ai_model": "gpt", "debug": "yes"}}}`
+	if err := os.WriteFile(path, []byte(doc), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadIsolated(dir, nil)
+	if err == nil || errors.Is(err, ErrCorrupt) || !strings.Contains(err.Error(), "profiles.default.debug") {
+		t.Fatalf("err = %v, want a warning about profiles.default.debug only", err)
+	}
+	if cfg.Theme != "amber" || cfg.Profile().AIModel != "gpt" || cfg.Profile().Debug {
+		t.Errorf("theme %q, model %q, debug %v: want the rest of the file kept", cfg.Theme, cfg.Profile().AIModel, cfg.Profile().Debug)
+	}
+
+	raw, err := readLayer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := flatten(raw)["profiles.default.debug"]; ok || raw["theme"] != "amber" {
+		t.Errorf("repaired file %v, want only debug removed", raw)
+	}
+	backups, _ := filepath.Glob(path + ".bak-*")
+	if len(backups) != 1 {
+		t.Fatalf("backups %v, want the original kept", backups)
+	}
+	if data, _ := os.ReadFile(backups[0]); string(data) != doc {
+		t.Errorf("backup holds %s, want the original", data)
+	}
+
+	// Once repaired it loads cleanly and saves
+	cfg, err = LoadIsolated(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(cfg); err != nil {
+		t.Errorf("saving the repaired config: %v", err)
+	}
+}
+
+func TestUnparseableConfigIsCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"theme": `), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadIsolated(dir, nil); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("err = %v, want ErrCorrupt", err)
+	}
+}
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Problem is a single issue found by Doctor
+type Problem struct {
+	Description string `json:"description"`
+	Fixed       bool   `json:"fixed"`
+	Fix         string `json:"fix"`
+}
+
+// Doctor inspects the config files and reports anything wrong with them.
+// When repair is true each problem in the user file that can be fixed
+// automatically is fixed and marked as such. The system file is only
+// reported on.
+func Doctor(repair bool) ([]Problem, error) {
+	var problems []Problem
+	report := func(desc, fix string, apply func() error) error {
+		p := Problem{Description: desc, Fix: fix}
+		if repair && apply != nil {
+			if err := apply(); err != nil {
+				return err
+			}
+			p.Fixed = true
+		}
+		problems = append(problems, p)
+		return nil
+	}
+	fresh := func() error {
+		return writeUser(Path(), map[string]any{"schema_version": CurrentSchemaVersion})
+	}
+
+	if _, err := readLayer(SystemPath); err != nil {
+		if err := report(SystemPath+": "+err.Error(), "ask an administrator to fix it", nil); err != nil {
+			return problems, err
+		}
+	}
+
+	path := Path()
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		err := report("config file "+path+" does not exist", "create it", fresh)
+		return problems, err
+	}
+	if err != nil {
+		return problems, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return problems, err
+	}
+
+	var probe map[string]any
+	if err := json.Unmarshal(data, &probe); err != nil {
+		err := report("config file is not valid JSON: "+err.Error(), "back it up and start a new one", func() error {
+			if _, err := backupConfig(path); err != nil {
+				return err
+			}
+			return fresh()
+		})
+		return problems, err
+	}
+
+	version := schemaVersion(probe)
+	if version > CurrentSchemaVersion {
+		err := report(
+			fmt.Sprintf("schema version %d is newer than this build supports (%d)", version, CurrentSchemaVersion),
+			"upgrade mainframe", nil,
+		)
+		return problems, err
+	}
+
+	raw, migrated, err := decode(data)
+	if err != nil {
+		if errors.Is(err, ErrCorrupt) {
+			err = report(err.Error(), "back it up and start a new one", func() error {
+				if _, err := backupConfig(path); err != nil {
+					return err
+				}
+				return fresh()
+			})
+		}
+		return problems, err
+	}
+
+	// Fixes below are applied to raw and written once at the end
+	dirty := false
+	if migrated {
+		var steps []string
+		for _, m := range migrations {
+			if m.from >= version {
+				steps = append(steps, m.summary)
+			}
+		}
+		err := report(
+			fmt.Sprintf("schema version %d is outdated (current is %d)", version, CurrentSchemaVersion),
+			"migrate: "+strings.Join(steps, "; "),
+			func() error { dirty = true; return nil },
+		)
+		if err != nil {
+			return problems, err
+		}
+	}
+
+	for _, bad := range validateRaw(raw) {
+		path := bad.path
+		err := report(path+": "+bad.reason, "remove it so the default applies", func() error {
+			deletePath(raw, path)
+			dirty = true
+			return nil
+		})
+		if err != nil {
+			return problems, err
+		}
+	}
+
+	if dirty {
+		if err := writeUser(path, raw); err != nil {
+			return problems, err
+		}
+	}
+
+	if keys, _ := raw["api_keys"].(map[string]any); len(keys) > 0 && info.Mode().Perm()&0077 != 0 {
+		err := report(
+			fmt.Sprintf("config file holds API keys but has mode %o", info.Mode().Perm()),
+			"chmod 600", func() error { return os.Chmod(path, 0600) },
+		)
+		if err != nil {
+			return problems, err
+		}
+	}
+
+	cfg, _ := assemble(path, raw, nil, false)
+	for _, name := range cfg.ProfileNames() {
+		p := cfg.Profiles[name]
+		if p.AIModel != "gpt" {
+			continue
+		}
+		probe := &Config{ActiveProfile: name, Profiles: cfg.Profiles, APIKeys: cfg.APIKeys}
+		if probe.APIKey() == "" {
+			desc := fmt.Sprintf("profile %q uses gpt but its api_key_ref %q resolves to no key", name, p.APIKeyRef)
+			if err := report(desc, "set a key in Settings", nil); err != nil {
+				return problems, err
+			}
+		}
+	}
+	if _, ok := cfg.Profiles[cfg.ActiveProfile]; !ok {
+		err := report(fmt.Sprintf("active profile %q does not exist", cfg.ActiveProfile), "switch profiles in Settings", nil)
+		if err != nil {
+			return problems, err
+		}
+	}
+
+	return problems, nil
+}
+
+type invalidKey struct {
+	path   string
+	reason string
+	// wrongType is set when the value cannot be decoded at all, not just
+	// rejected
+	wrongType bool
+}
+
+// wrongTypes lists the keys in a config document that would stop it from
+// decoding
+func wrongTypes(raw map[string]any) []invalidKey {
+	var bad []invalidKey
+	for _, b := range validateRaw(raw) {
+		if b.wrongType {
+			bad = append(bad, b)
+		}
+	}
+	return bad
+}
+
+// validateRaw lists every key in a config document that is unknown or
+// holds a value of the wrong type
+func validateRaw(raw map[string]any) []invalidKey {
+	var bad []invalidKey
+	for _, key := range sortedKeys(raw) {
+		v := raw[key]
+		switch key {
+		case "schema_version":
+			if _, ok := v.(float64); !ok {
+				bad = append(bad, invalidKey{key, fmt.Sprintf("expected a number, got %v", v), true})
+			}
+		case "color", "background", "accessible", "language", "metrics_interval":
+			f, _ := lookupField(key)
+			bad = append(bad, checkValue(key, f, v)...)
+		case "active_profile", "theme", "presence_dir", "instructor":
+			if _, ok := v.(string); !ok {
+				bad = append(bad, invalidKey{key, fmt.Sprintf("expected a string, got %v", v), true})
+			}
+		case "api_keys":
+			keys, ok := v.(map[string]any)
+			if !ok {
+				bad = append(bad, invalidKey{key, "expected an object of named keys", true})
+				continue
+			}
+			for _, name := range sortedKeys(keys) {
+				if _, ok := keys[name].(string); !ok {
+					bad = append(bad, invalidKey{key + "." + name, "expected a string", true})
+				}
+			}
+		case "profiles":
+			profiles, ok := v.(map[string]any)
+			if !ok {
+				bad = append(bad, invalidKey{key, "expected an object of profiles", true})
+				continue
+			}
+			for _, name := range sortedKeys(profiles) {
+				profile, ok := profiles[name].(map[string]any)
+				if !ok {
+					bad = append(bad, invalidKey{key + "." + name, "expected an object", true})
+					continue
+				}
+				for _, k := range sortedKeys(profile) {
+					path := key + "." + name + "." + k
+					if k == "features" {
+						bad = append(bad, checkFeatures(path, profile[k])...)
+						continue
+					}
+					f, err := lookupField(k)
+					if err != nil || !f.inProfile {
+						bad = append(bad, invalidKey{path, "unknown key", false})
+					} else {
+						bad = append(bad, checkValue(path, f, profile[k])...)
+					}
+				}
+			}
+		default:
+			bad = append(bad, invalidKey{key, "unknown key", false})
+		}
+	}
+	return bad
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkFeatures validates a profile's feature flag settings
+func checkFeatures(path string, v any) []invalidKey {
+	flags, ok := v.(map[string]any)
+	if !ok {
+		return []invalidKey{{path, "expected an object of feature flags", true}}
+	}
+	var bad []invalidKey
+	for _, name := range sortedKeys(flags) {
+		f, ok := LookupFeature(name)
+		if !ok {
+			bad = append(bad, invalidKey{path + "." + name, "unknown feature flag", false})
+		} else if _, ok := flags[name].(bool); !ok {
+			bad = append(bad, invalidKey{path + "." + name, fmt.Sprintf("expected true or false, got %v", flags[name]), true})
+		} else if f.Expired(time.Now()) {
+			bad = append(bad, invalidKey{path + "." + name, "feature flag expired on " + f.Expires.Format("2006-01-02"), false})
+		}
+	}
+	return bad
+}
+
+// checkValue reports v, as decoded from JSON, at path if it is not a
+// valid value for f
+func checkValue(path string, f Field, v any) []invalidKey {
+	if f.Bool {
+		if _, ok := v.(bool); !ok {
+			return []invalidKey{{path, fmt.Sprintf("expected true or false, got %v", v), true}}
+		}
+		return nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return []invalidKey{{path, fmt.Sprintf("expected a string, got %v", v), true}}
+	}
+	if err := f.set(Default(), s); err != nil {
+		return []invalidKey{{path, err.Error(), false}}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stability says how far along a feature flag is
+type Stability string
+
+const (
+	StabilityExperimental Stability = "experimental"
+	StabilityBeta         Stability = "beta"
+	StabilityStable       Stability = "stable"
+)
+
+// Feature is a named flag that gates a piece of behavior. Each profile can
+// turn a flag on or off; flags it leaves alone follow Default, or are all
+// on when the profile's experimental setting is.
+type Feature struct {
+	Name        string
+	Description string
+	Default     bool
+	Stability   Stability
+	// Expires is when the flag should be gone from the code. An expired
+	// flag ignores profile settings and always follows Default.
+	Expires time.Time
+}
+
+// Expired reports whether the flag is past its expiry date at now
+func (f Feature) Expired(now time.Time) bool {
+	return !f.Expires.IsZero() && !now.Before(f.Expires)
+}
+
+// Feature flag names
+const (
+	FeatureLogFollow          = "log_follow"
+	FeaturePresenceBroadcasts = "presence_broadcasts"
+	FeatureLogRelativeTime    = "log_relative_time"
+)
+
+var features = []Feature{
+	{
+		Name:        FeatureLogFollow,
+		Description: "follow the current log file live in the log viewer",
+		Default:     true,
+		Stability:   StabilityBeta,
+		Expires:     time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC),
+	},
+	{
+		Name:        FeaturePresenceBroadcasts,
+		Description: "show instructor broadcasts as a dialog",
+		Default:     true,
+		Stability:   StabilityBeta,
+	},
+	{
+		Name:        FeatureLogRelativeTime,
+		Description: "show how long ago each entry was logged in the log viewer",
+		Stability:   StabilityExperimental,
+	},
+}
+
+// Features lists every registered feature flag
+func Features() []Feature {
+	return features
+}
+
+// LookupFeature finds a feature flag by name
+func LookupFeature(name string) (Feature, bool) {
+	for _, f := range features {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Feature{}, false
+}
+
+// Enabled reports whether the named feature is on for the active profile
+// at now, which decides whether the flag has expired. Unknown flags are
+// off.
+func (c *Config) Enabled(name string, now time.Time) bool {
+	f, ok := LookupFeature(name)
+	if !ok {
+		return false
+	}
+	if f.Expired(now) {
+		return f.Default
+	}
+	p := c.Profile()
+	if on, ok := p.Features[name]; ok {
+		return on
+	}
+	if p.Experimental && f.Stability != StabilityStable {
+		return true
+	}
+	return f.Default
+}
+
+// SetFeature turns the named feature on or off for the active profile
+func (c *Config) SetFeature(name string, on bool) error {
+	if _, ok := LookupFeature(name); !ok {
+		return fmt.Errorf("unknown feature flag %q", name)
+	}
+	p := c.Profile()
+	if p.Features == nil {
+		p.Features = map[string]bool{}
+	}
+	p.Features[name] = on
+	return nil
+}
+
+// ResetFeature drops the active profile's setting for the named feature so
+// it follows its default again
+func (c *Config) ResetFeature(name string) {
+	delete(c.Profile().Features, name)
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExperimentalTurnsOnExperimentalFlags(t *testing.T) {
+	now := time.Date(2026, time.January, 5, 9, 30, 0, 0, time.UTC)
+	f, ok := LookupFeature(FeatureLogRelativeTime)
+	if !ok || f.Default || f.Stability != StabilityExperimental {
+		t.Fatalf("%s should be an experimental flag that is off by default: %+v", FeatureLogRelativeTime, f)
+	}
+
+	c := Default()
+	if c.Enabled(FeatureLogRelativeTime, now) {
+		t.Errorf("%s is on without experimental", FeatureLogRelativeTime)
+	}
+	c.Profile().Experimental = true
+	if !c.Enabled(FeatureLogRelativeTime, now) {
+		t.Errorf("experimental did not turn %s on", FeatureLogRelativeTime)
+	}
+
+	// A setting of the flag's own still wins
+	if err := c.SetFeature(FeatureLogRelativeTime, false); err != nil {
+		t.Fatal(err)
+	}
+	if c.Enabled(FeatureLogRelativeTime, now) {
+		t.Errorf("%s is on although the profile turned it off", FeatureLogRelativeTime)
+	}
+}
+
+func TestEnabledFollowsTheGivenClock(t *testing.T) {
+	f, _ := LookupFeature(FeatureLogFollow)
+	c := Default()
+	if err := c.SetFeature(f.Name, !f.Default); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Enabled(f.Name, f.Expires.Add(-time.Hour)); got == f.Default {
+		t.Errorf("before expiry %s = %v, want the profile's %v", f.Name, got, !f.Default)
+	}
+	if got := c.Enabled(f.Name, f.Expires); got != f.Default {
+		t.Errorf("at expiry %s = %v, want the default %v", f.Name, got, f.Default)
+	}
+}
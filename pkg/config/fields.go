@@ -0,0 +1,268 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field describes one user-facing setting. Every setting can be set from
+// a config file, from the environment under Env, and from the command line
+// as --Flag. Settings other than the profile itself apply to the active
+// profile.
+type Field struct {
+	Key         string
+	Env         string
+	Flag        string
+	Bool        bool
+	Description string
+	Secret      bool
+
+	// inProfile marks settings stored inside each profile under Key
+	inProfile bool
+	// path locates the setting in the config document
+	path func(*Config) string
+	get  func(*Config) string
+	set  func(*Config, string) error
+}
+
+var fields = []Field{
+	{
+		Key:         "profile",
+		Description: "name of the active profile",
+		path:        func(c *Config) string { return "active_profile" },
+		get:         func(c *Config) string { return c.ActiveProfile },
+		set:         func(c *Config, v string) error { return c.UseProfile(v) },
+	},
+	{
+		Key:         "theme",
+		Description: "color theme",
+		path:        func(c *Config) string { return "theme" },
+		get:         func(c *Config) string { return c.Theme },
+		set:         func(c *Config, v string) error { c.Theme = v; return nil },
+	},
+	{
+		Key:         "color",
+		Description: "colors to render: auto, truecolor, 256, 16 or none",
+		path:        func(c *Config) string { return "color" },
+		get:         func(c *Config) string { return c.Color },
+		set: func(c *Config, v string) error {
+			return oneOf(v, []string{"", "auto", "truecolor", "256", "16", "none"}, func() { c.Color = v })
+		},
+	},
+	{
+		Key:         "background",
+		Description: "terminal background: auto, dark or light",
+		path:        func(c *Config) string { return "background" },
+		get:         func(c *Config) string { return c.Background },
+		set: func(c *Config, v string) error {
+			return oneOf(v, []string{"", "auto", "dark", "light"}, func() { c.Background = v })
+		},
+	},
+	{
+		Key:         "accessible",
+		Description: "linear output for screen readers, without borders or icons",
+		Bool:        true,
+		path:        func(c *Config) string { return "accessible" },
+		get:         func(c *Config) string { return strconv.FormatBool(c.Accessible) },
+		set:         boolSetter(func(c *Config, b bool) { c.Accessible = b }),
+	},
+	{
+		Key:         "language",
+		Description: "language of the TUI: auto, en, es or pt",
+		path:        func(c *Config) string { return "language" },
+		get:         func(c *Config) string { return c.Language },
+		set: func(c *Config, v string) error {
+			return oneOf(v, []string{"", "auto", "en", "es", "pt"}, func() { c.Language = v })
+		},
+	},
+	{
+		Key:         "presence_dir",
+		Description: "shared directory for lab presence heartbeats (empty to disable)",
+		path:        func(c *Config) string { return "presence_dir" },
+		get:         func(c *Config) string { return c.PresenceDir },
+		set:         func(c *Config, v string) error { c.PresenceDir = v; return nil },
+	},
+	{
+		Key:         "instructor",
+		Description: "login name of the instructor whose broadcasts are shown",
+		path:        func(c *Config) string { return "instructor" },
+		get:         func(c *Config) string { return c.Instructor },
+		set:         func(c *Config, v string) error { c.Instructor = v; return nil },
+	},
+	{
+		Key:         "metrics_interval",
+		Description: "how often Performance Metrics samples, e.g. 1s",
+		path:        func(c *Config) string { return "metrics_interval" },
+		get:         func(c *Config) string { return c.SampleInterval().String() },
+		set: func(c *Config, v string) error {
+			if v == "" {
+				c.MetricsInterval = ""
+				return nil
+			}
+			d, err := time.ParseDuration(v)
+			if err != nil || d < MinMetricsInterval {
+				return fmt.Errorf("expected a duration of at least %s, got %q", MinMetricsInterval, v)
+			}
+			c.MetricsInterval = v
+			return nil
+		},
+	},
+	{
+		Key:         "ai_model",
+		Description: "AI backend to use (local or gpt)",
+		inProfile:   true,
+		get:         func(c *Config) string { return c.Profile().AIModel },
+		set: func(c *Config, v string) error {
+			if v != "local" && v != "gpt" {
+				return fmt.Errorf("ai_model must be \"local\" or \"gpt\", got %q", v)
+			}
+			c.Profile().AIModel = v
+			return nil
+		},
+	},
+	{
+		Key:         "api_key_ref",
+		Description: "stored API key the profile uses, or env:NAME",
+		inProfile:   true,
+		get:         func(c *Config) string { return c.Profile().APIKeyRef },
+		set:         func(c *Config, v string) error { c.Profile().APIKeyRef = v; return nil },
+	},
+	{
+		Key:         "api_key",
+		Description: "OpenAI API key",
+		Secret:      true,
+		path:        func(c *Config) string { return "api_keys." + c.Profile().APIKeyRef },
+		get:         func(c *Config) string { return c.APIKey() },
+		set:         func(c *Config, v string) error { c.SetAPIKey(v); return nil },
+	},
+	{
+		Key:         "api_base_url",
+		Description: "OpenAI compatible API URL",
+		inProfile:   true,
+		get:         func(c *Config) string { return c.Profile().BaseURL() },
+		set:         func(c *Config, v string) error { c.Profile().APIBaseURL = v; return nil },
+	},
+	{
+		Key:         "model_path",
+		Description: "path to local model weights",
+		inProfile:   true,
+		get:         func(c *Config) string { return c.Profile().ModelPath },
+		set:         func(c *Config, v string) error { c.Profile().ModelPath = v; return nil },
+	},
+	{
+		Key:         "model_server",
+		Description: "URL of the llama.cpp compatible server running local models",
+		inProfile:   true,
+		get:         func(c *Config) string { return c.Profile().Server() },
+		set:         func(c *Config, v string) error { c.Profile().ModelServer = v; return nil },
+	},
+	{
+		Key:         "debug",
+		Description: "verbose debug output",
+		Bool:        true,
+		inProfile:   true,
+		get:         func(c *Config) string { return strconv.FormatBool(c.Profile().Debug) },
+		set:         boolSetter(func(c *Config, b bool) { c.Profile().Debug = b }),
+	},
+	{
+		Key:         "logs",
+		Description: "write logs to disk",
+		Bool:        true,
+		inProfile:   true,
+		get:         func(c *Config) string { return strconv.FormatBool(c.Profile().Logs) },
+		set:         boolSetter(func(c *Config, b bool) { c.Profile().Logs = b }),
+	},
+	{
+		Key:         "experimental",
+		Description: "enable experimental features",
+		Bool:        true,
+		inProfile:   true,
+		get:         func(c *Config) string { return strconv.FormatBool(c.Profile().Experimental) },
+		set:         boolSetter(func(c *Config, b bool) { c.Profile().Experimental = b }),
+	},
+}
+
+func init() {
+	for i := range fields {
+		f := &fields[i]
+		f.Env = envName(f.Key)
+		f.Flag = flagName(f.Key)
+		if f.inProfile {
+			key := f.Key
+			f.path = func(c *Config) string { return "profiles." + c.ActiveProfile + "." + key }
+		}
+	}
+}
+
+// Bounds and default of metrics_interval
+const (
+	MinMetricsInterval     = 100 * time.Millisecond
+	DefaultMetricsInterval = time.Second
+)
+
+// SampleInterval returns metrics_interval as a duration, falling back to
+// DefaultMetricsInterval if it is unset or invalid
+func (c *Config) SampleInterval() time.Duration {
+	d, err := time.ParseDuration(c.MetricsInterval)
+	if err != nil || d < MinMetricsInterval {
+		return DefaultMetricsInterval
+	}
+	return d
+}
+
+// Fields lists every setting in display order
+func Fields() []Field {
+	return fields
+}
+
+func lookupField(key string) (Field, error) {
+	for _, f := range fields {
+		if f.Key == key {
+			return f, nil
+		}
+	}
+	return Field{}, fmt.Errorf("unknown config key %q", key)
+}
+
+// Get returns the effective value of key as a string
+func (c *Config) Get(key string) (string, error) {
+	f, err := lookupField(key)
+	if err != nil {
+		return "", err
+	}
+	return f.get(c), nil
+}
+
+// Set parses value and assigns it to key. The change is only written to
+// disk by Save.
+func (c *Config) Set(key, value string) error {
+	f, err := lookupField(key)
+	if err != nil {
+		return err
+	}
+	return f.set(c, value)
+}
+
+// oneOf calls assign if v is one of allowed
+func oneOf(v string, allowed []string, assign func()) error {
+	for _, a := range allowed {
+		if v == a {
+			assign()
+			return nil
+		}
+	}
+	return fmt.Errorf("expected one of %s, got %q", strings.Join(allowed[1:], ", "), v)
+}
+
+func boolSetter(assign func(*Config, bool)) func(*Config, string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("expected true or false, got %q", v)
+		}
+		assign(c, b)
+		return nil
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Layer identifies where an effective setting came from. Later layers
+// override earlier ones.
+type Layer int
+
+const (
+	LayerDefault Layer = iota
+	LayerSystem
+	LayerUser
+	LayerEnv
+	LayerFlag
+)
+
+func (l Layer) String() string {
+	switch l {
+	case LayerSystem:
+		return "system"
+	case LayerUser:
+		return "user"
+	case LayerEnv:
+		return "env"
+	case LayerFlag:
+		return "flag"
+	}
+	return "default"
+}
+
+// SystemPath is the machine-wide config file shared by every user
+var SystemPath = "/etc/mainframe/config.json"
+
+// Dir returns the per-user config directory. $XDG_CONFIG_HOME/mainframe is
+// used when XDG_CONFIG_HOME is set, otherwise ~/.mainframe.
+func Dir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "mainframe")
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = "."
+	}
+	return filepath.Join(homeDir, ".mainframe")
+}
+
+// Path returns the location of the per-user config.json
+func Path() string {
+	return filepath.Join(Dir(), "config.json")
+}
+
+// ThemesDir holds the user's custom *.toml color themes
+func ThemesDir() string {
+	return filepath.Join(Dir(), "themes")
+}
+
+// KeysPath is the user's key binding file
+func KeysPath() string {
+	return filepath.Join(Dir(), "keys.toml")
+}
+
+// LogsDir holds the log files written when logs are enabled
+func LogsDir() string {
+	return filepath.Join(Dir(), "logs")
+}
+
+// Dir is the directory of the user file the config is saved to. The key
+// bindings, themes and logs that go with the config are kept there too.
+func (c *Config) Dir() string {
+	return filepath.Dir(c.File())
+}
+
+// ThemesDir is ThemesDir for the config's own directory
+func (c *Config) ThemesDir() string {
+	return filepath.Join(c.Dir(), "themes")
+}
+
+// KeysPath is KeysPath for the config's own directory
+func (c *Config) KeysPath() string {
+	return filepath.Join(c.Dir(), "keys.toml")
+}
+
+// LogsDir is LogsDir for the config's own directory
+func (c *Config) LogsDir() string {
+	return filepath.Join(c.Dir(), "logs")
+}
+
+// ModelsDir holds local model weights downloaded by Mainframe
+func ModelsDir() string {
+	return filepath.Join(Dir(), "models")
+}
+
+func envName(key string) string {
+	return "MAINFRAME_" + strings.ToUpper(key)
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// origin remembers how a Config was assembled so Save only writes the
+// settings the user actually changed back to the user file
+type origin struct {
+	// path is the user file the config was loaded from
+	path string
+	// sources maps document paths to the file layer that set them
+	sources map[string]Layer
+	// overrides maps field keys set from the environment or flags
+	overrides map[string]Layer
+	// loaded is the flattened document as of the last Load or Save
+	loaded map[string]string
+	user   map[string]any
+	// disk is the flattened user file as last read or written, used to
+	// notice edits made by someone else
+	disk  map[string]string
+	flags map[string]string
+	// pending holds the disk version while a Conflict is unresolved
+	pending *Config
+	// readOnly is why the user file could not be read, if it could not
+	readOnly error
+	// isolated configs take nothing from the system file or environment
+	isolated bool
+}
+
+// Source reports which layer set the effective value of key
+func (c *Config) Source(key string) Layer {
+	if c.origin == nil {
+		return LayerDefault
+	}
+	if layer, ok := c.origin.overrides[key]; ok {
+		return layer
+	}
+	f, err := lookupField(key)
+	if err != nil {
+		return LayerDefault
+	}
+	return c.origin.sources[f.path(c)]
+}
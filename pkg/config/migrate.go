@@ -0,0 +1,75 @@
+package config
+
+import "fmt"
+
+// migration upgrades a raw config document from one schema version to the
+// next. Migrations work on the decoded JSON so they can rename or reshape
+// fields that no longer exist on Config.
+type migration struct {
+	from    int
+	apply   func(raw map[string]any) error
+	summary string
+}
+
+// migrations must stay ordered by from, with one entry per version step
+var migrations = []migration{
+	{
+		from:    0,
+		summary: "add schema_version and drop an empty ai_model",
+		apply: func(raw map[string]any) error {
+			if model, ok := raw["ai_model"].(string); ok && model == "" {
+				delete(raw, "ai_model")
+			}
+			return nil
+		},
+	},
+	{
+		from:    1,
+		summary: "move model and developer settings into the default profile",
+		apply: func(raw map[string]any) error {
+			profile := map[string]any{}
+			for _, key := range []string{"ai_model", "debug", "logs", "experimental"} {
+				if v, ok := raw[key]; ok {
+					profile[key] = v
+					delete(raw, key)
+				}
+			}
+			if key, ok := raw["api_key"]; ok {
+				delete(raw, "api_key")
+				if key != "" {
+					raw["api_keys"] = map[string]any{DefaultProfile: key}
+					profile["api_key_ref"] = DefaultProfile
+				}
+			}
+			if len(profile) > 0 {
+				raw["profiles"] = map[string]any{DefaultProfile: profile}
+				raw["active_profile"] = DefaultProfile
+			}
+			return nil
+		},
+	},
+}
+
+// schemaVersion reads schema_version from a raw config. Files written
+// before versioning existed have no field and count as version 0.
+func schemaVersion(raw map[string]any) int {
+	v, ok := raw["schema_version"].(float64)
+	if !ok {
+		return 0
+	}
+	return int(v)
+}
+
+// migrate runs every migration from version from up to CurrentSchemaVersion
+func migrate(raw map[string]any, from int) error {
+	for _, m := range migrations {
+		if m.from < from {
+			continue
+		}
+		if err := m.apply(raw); err != nil {
+			return fmt.Errorf("migrating config from version %d: %w", m.from, err)
+		}
+		raw["schema_version"] = m.from + 1
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile used when none has been chosen
+const DefaultProfile = "default"
+
+// Profile is a named set of model and developer settings. Users switch
+// between profiles instead of editing settings back and forth.
+type Profile struct {
+	AIModel      string `json:"ai_model"`
+	APIKeyRef    string `json:"api_key_ref,omitempty"`
+	ModelPath    string `json:"model_path,omitempty"`
+	ModelServer  string `json:"model_server,omitempty"`
+	APIBaseURL   string `json:"api_base_url,omitempty"`
+	Debug        bool   `json:"debug"`
+	Logs         bool   `json:"logs"`
+	Experimental bool   `json:"experimental"`
+	// Features holds the feature flags the profile sets explicitly
+	Features map[string]bool `json:"features,omitempty"`
+}
+
+// DefaultModelServer is where a local llama.cpp server listens by default
+const DefaultModelServer = "http://127.0.0.1:8080"
+
+// Server returns the local model server URL, or DefaultModelServer if the
+// profile does not set one
+func (p *Profile) Server() string {
+	if p.ModelServer == "" {
+		return DefaultModelServer
+	}
+	return p.ModelServer
+}
+
+// DefaultAPIBaseURL is the OpenAI API used unless a profile names another
+const DefaultAPIBaseURL = "https://api.openai.com/v1"
+
+// BaseURL returns the OpenAI compatible API URL of the profile
+func (p *Profile) BaseURL() string {
+	if p.APIBaseURL == "" {
+		return DefaultAPIBaseURL
+	}
+	return p.APIBaseURL
+}
+
+// Endpoint is the URL the profile's AI model is reached at: the model
+// server for local models and the API otherwise
+func (p *Profile) Endpoint() string {
+	if p.AIModel == "local" {
+		return p.Server()
+	}
+	return p.BaseURL()
+}
+
+// Profile returns the active profile, creating it if it does not exist yet
+func (c *Config) Profile() *Profile {
+	if c.Profiles == nil {
+		c.Profiles = map[string]*Profile{}
+	}
+	if c.ActiveProfile == "" {
+		c.ActiveProfile = DefaultProfile
+	}
+	p, ok := c.Profiles[c.ActiveProfile]
+	if !ok {
+		p = &Profile{AIModel: defaultProfile.AIModel}
+		c.Profiles[c.ActiveProfile] = p
+	}
+	return p
+}
+
+// ProfileNames lists the configured profiles in alphabetical order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UseProfile makes name the active profile
+func (c *Config) UseProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	c.ActiveProfile = name
+	return nil
+}
+
+// AddProfile creates a new profile as a copy of the active one
+func (c *Config) AddProfile(name string) error {
+	if name == "" || strings.ContainsAny(name, ". ") {
+		return fmt.Errorf("profile names must be non-empty and contain no dots or spaces")
+	}
+	if _, ok := c.Profiles[name]; ok {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	p := *c.Profile()
+	p.Features = maps.Clone(p.Features)
+	c.Profiles[name] = &p
+	return nil
+}
+
+// APIKey resolves the active profile's key reference. A reference of the
+// form env:NAME reads the key from the environment, unless the config is
+// isolated, anything else names an entry in APIKeys.
+func (c *Config) APIKey() string {
+	ref := c.Profile().APIKeyRef
+	if env, ok := strings.CutPrefix(ref, "env:"); ok {
+		if c.origin != nil && c.origin.isolated {
+			return ""
+		}
+		return os.Getenv(env)
+	}
+	return c.APIKeys[ref]
+}
+
+// SetAPIKey stores key under the active profile's own name and points the
+// profile at it, so profiles that shared a key with it keep theirs
+func (c *Config) SetAPIKey(key string) {
+	p := c.Profile()
+	p.APIKeyRef = c.ActiveProfile
+	if c.APIKeys == nil {
+		c.APIKeys = map[string]string{}
+	}
+	c.APIKeys[p.APIKeyRef] = key
+}
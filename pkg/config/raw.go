@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Config documents are layered as decoded JSON so that a layer only
+// overrides the keys it actually contains. Nested keys are addressed by
+// dotted paths such as "profiles.office.debug".
+
+func toRaw(c *Config) map[string]any {
+	data, _ := json.Marshal(c)
+	var raw map[string]any
+	json.Unmarshal(data, &raw)
+	return raw
+}
+
+func fromRaw(raw map[string]any, c *Config) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, c)
+}
+
+// mergeRaw copies every key of src into dst, descending into objects, and
+// records layer as the source of each leaf it writes
+func mergeRaw(dst, src map[string]any, prefix string, layer Layer, sources map[string]Layer) {
+	for k, v := range src {
+		path := prefix + k
+		if obj, ok := v.(map[string]any); ok {
+			sub, ok := dst[k].(map[string]any)
+			if !ok {
+				sub = map[string]any{}
+				dst[k] = sub
+			}
+			mergeRaw(sub, obj, path+".", layer, sources)
+			continue
+		}
+		dst[k] = v
+		sources[path] = layer
+	}
+}
+
+// flatten maps the dotted path of every leaf in raw to its JSON encoding
+func flatten(raw map[string]any) map[string]string {
+	out := map[string]string{}
+	var walk func(map[string]any, string)
+	walk = func(m map[string]any, prefix string) {
+		for k, v := range m {
+			if obj, ok := v.(map[string]any); ok {
+				walk(obj, prefix+k+".")
+				continue
+			}
+			data, _ := json.Marshal(v)
+			out[prefix+k] = string(data)
+		}
+	}
+	walk(raw, "")
+	return out
+}
+
+func sameFlat(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+// setPath stores the JSON-encoded value at path, creating objects on the
+// way
+func setPath(raw map[string]any, path, value string) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		sub, ok := raw[k].(map[string]any)
+		if !ok {
+			sub = map[string]any{}
+			raw[k] = sub
+		}
+		raw = sub
+	}
+	var v any
+	json.Unmarshal([]byte(value), &v)
+	raw[keys[len(keys)-1]] = v
+}
+
+func deletePath(raw map[string]any, path string) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		sub, ok := raw[k].(map[string]any)
+		if !ok {
+			return
+		}
+		raw = sub
+	}
+	delete(raw, keys[len(keys)-1])
+}
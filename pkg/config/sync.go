@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrConflict is returned by Save when the user file was changed by
+// someone else since it was last read. Call Reload to pick up the change.
+var ErrConflict = errors.New("config file changed on disk")
+
+// Change is a setting edited both in memory and on disk
+type Change struct {
+	Path   string
+	Mine   string
+	Theirs string
+}
+
+// Conflict lists the settings that differ between unsaved in-memory edits
+// and a newer version on disk. It is resolved with Config.Resolve.
+type Conflict struct {
+	Changes []Change
+}
+
+// Resolution picks how a Conflict is settled
+type Resolution int
+
+const (
+	// ResolveMerge keeps the disk version and reapplies local edits that
+	// do not clash with it
+	ResolveMerge Resolution = iota
+	// ResolveMine overwrites the disk version with the in-memory one
+	ResolveMine
+	// ResolveTheirs discards local edits in favour of the disk version
+	ResolveTheirs
+)
+
+// Reload re-reads the config files after they changed on disk. Edits that
+// do not overlap are merged and saved. If a setting was changed both in
+// memory and on disk nothing is modified and the clash is returned to be
+// settled with Resolve.
+func (c *Config) Reload() (*Conflict, error) {
+	if c.origin == nil {
+		return nil, nil
+	}
+	theirs, err := c.readTheirs()
+	if err != nil {
+		return nil, err
+	}
+
+	conflict := c.compare(theirs)
+	if len(conflict.Changes) > 0 {
+		c.origin.pending = theirs
+		return conflict, nil
+	}
+	return nil, c.adopt(theirs, ResolveMerge)
+}
+
+// Resolve settles the conflict returned by the last Reload
+func (c *Config) Resolve(how Resolution) error {
+	theirs := c.origin.pending
+	if theirs == nil {
+		return nil
+	}
+	c.origin.pending = nil
+	return c.adopt(theirs, how)
+}
+
+func (c *Config) readTheirs() (*Config, error) {
+	user, err := readLayer(c.origin.path)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		user = map[string]any{}
+	}
+	return assemble(c.origin.path, user, c.origin.flags, c.origin.isolated)
+}
+
+// localChanges lists the paths edited in memory since the last load or save
+func (c *Config) localChanges() (map[string]string, []string) {
+	mine := flatten(toRaw(c))
+	var paths []string
+	for p, v := range mine {
+		if c.origin.loaded[p] != v {
+			paths = append(paths, p)
+		}
+	}
+	for p := range c.origin.loaded {
+		if _, ok := mine[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	return mine, paths
+}
+
+func (c *Config) compare(theirs *Config) *Conflict {
+	base, their := c.origin.loaded, theirs.origin.loaded
+	mine, local := c.localChanges()
+
+	conflict := &Conflict{}
+	for _, p := range local {
+		if their[p] != base[p] && their[p] != mine[p] {
+			conflict.Changes = append(conflict.Changes, Change{Path: p, Mine: mine[p], Theirs: their[p]})
+		}
+	}
+	return conflict
+}
+
+// adopt replaces c with theirs and reapplies local edits as chosen by how,
+// saving any that survive
+func (c *Config) adopt(theirs *Config, how Resolution) error {
+	mine, local := c.localChanges()
+	conflicting := map[string]bool{}
+	for _, ch := range c.compare(theirs).Changes {
+		conflicting[ch.Path] = true
+	}
+
+	raw := toRaw(theirs)
+	switch how {
+	case ResolveMine:
+		raw = toRaw(c)
+		local = nil
+		for p := range flatten(raw) {
+			local = append(local, p)
+		}
+	case ResolveTheirs:
+		local = nil
+	}
+
+	keep := 0
+	for _, p := range local {
+		if how == ResolveMerge && conflicting[p] {
+			continue
+		}
+		if v, ok := mine[p]; ok {
+			setPath(raw, p, v)
+		} else {
+			deletePath(raw, p)
+		}
+		keep++
+	}
+
+	merged := &Config{}
+	if err := fromRaw(raw, merged); err != nil {
+		return err
+	}
+	merged.origin = theirs.origin
+	merged.normalize()
+	*c = *merged
+
+	if keep > 0 {
+		return Save(c)
+	}
+	return nil
+}
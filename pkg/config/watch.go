@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher reports when the user or system config file changes on disk
+type Watcher struct {
+	fs      *fsnotify.Watcher
+	path    string
+	changes chan struct{}
+}
+
+// debounce collapses the burst of events editors produce when saving
+const debounce = 100 * time.Millisecond
+
+// Watch starts watching the config files. The containing directories are
+// watched rather than the files so that editors which save by renaming a
+// new file into place are noticed too.
+func Watch() (*Watcher, error) {
+	return WatchFrom(Dir())
+}
+
+// WatchFrom is Watch for a user file kept in dir, as loaded by LoadFrom
+func WatchFrom(dir string) (*Watcher, error) {
+	fs, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		fs.Close()
+		return nil, err
+	}
+	if err := fs.Add(dir); err != nil {
+		fs.Close()
+		return nil, err
+	}
+	// The system directory is optional on most machines
+	if _, err := os.Stat(filepath.Dir(SystemPath)); err == nil {
+		fs.Add(filepath.Dir(SystemPath))
+	}
+
+	w := &Watcher{fs: fs, path: filepath.Join(dir, "config.json"), changes: make(chan struct{}, 1)}
+	go w.run()
+	return w, nil
+}
+
+// Changes delivers a value after each burst of writes to a config file.
+// The channel is closed when the watcher is.
+func (w *Watcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *Watcher) Close() error {
+	return w.fs.Close()
+}
+
+func (w *Watcher) run() {
+	defer close(w.changes)
+
+	user, system := filepath.Clean(w.path), filepath.Clean(SystemPath)
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case ev, ok := <-w.fs.Events:
+			if !ok {
+				return
+			}
+			name := filepath.Clean(ev.Name)
+			if name == user || name == system {
+				timer.Reset(debounce)
+			}
+		case _, ok := <-w.fs.Errors:
+			if !ok {
+				return
+			}
+		case <-timer.C:
+			select {
+			case w.changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package i18n
+
+// spanish translates the TUI into Spanish
+var spanish = map[string]string{
+	// Shared
+	"on":                       "activado",
+	"off":                      "desactivado",
+	"ON":                       "SÍ",
+	"OFF":                      "NO",
+	"(selected)":               "(seleccionado)",
+	"(unset)":                  "(sin definir)",
+	"(none)":                   "(ninguno)",
+	"none":                     "ninguno",
+	"default":                  "predeterminado",
+	"never":                    "nunca",
+	"all":                      "todos",
+	"n/a":                      "n/d",
+	"Error":                    "Error",
+	"Screen":                   "Pantalla",
+	"Selected":                 "Seleccionado",
+	"%d of %d on":              "%d de %d activados",
+	"[ACTIVE]":                 "[ACTIVO]",
+	"[INACTIVE]":               "[INACTIVO]",
+	"AVAILABLE":                "DISPONIBLE",
+	"lines %d-%d of %d • %d%%": "líneas %d-%d de %d • %d%%",
+
+	// Key help
+	"up":                                 "arriba",
+	"down":                               "abajo",
+	"top":                                "inicio",
+	"bottom":                             "final",
+	"select":                             "elegir",
+	"back":                               "volver",
+	"help":                               "ayuda",
+	"quit":                               "salir",
+	"debug overlay":                      "panel de depuración",
+	"command palette":                    "paleta de comandos",
+	"switch panel":                       "cambiar panel",
+	"page up":                            "página arriba",
+	"page down":                          "página abajo",
+	"save":                               "guardar",
+	"cancel":                             "cancelar",
+	"merge":                              "combinar",
+	"override with mine":                 "usar los míos",
+	"take theirs":                        "usar los del disco",
+	"new profile":                        "nuevo perfil",
+	"refresh":                            "actualizar",
+	"broadcast":                          "difundir",
+	"sample faster":                      "muestrear más rápido",
+	"sample slower":                      "muestrear más lento",
+	"reset":                              "restablecer",
+	"search":                             "buscar",
+	"next match":                         "siguiente coincidencia",
+	"previous match":                     "coincidencia anterior",
+	"jump to time":                       "ir a una hora",
+	"level":                              "nivel",
+	"component":                          "componente",
+	"older file":                         "archivo anterior",
+	"newer file":                         "archivo siguiente",
+	"move":                               "mover",
+	"go":                                 "ir",
+	"close":                              "cerrar",
+	"close help":                         "cerrar ayuda",
+	"create":                             "crear",
+	"send":                               "enviar",
+	"toggle":                             "alternar",
+	"toggle or open":                     "alternar o abrir",
+	"run again":                          "repetir",
+	"back to main menu":                  "volver al menú principal",
+	"back to settings":                   "volver a la configuración",
+	"new profile (on Profile)":           "nuevo perfil (en Perfil)",
+	"debug overlay (with Debug Mode on)": "panel de depuración (con el Modo de depuración activado)",
+
+	// Screens
+	"Home":                "Inicio",
+	"Settings":            "Configuración",
+	"Developer Options":   "Opciones de desarrollador",
+	"Feature Flags":       "Funciones experimentales",
+	"Model Configuration": "Configuración del modelo",
+	"Logs":                "Registros",
+	"Performance Metrics": "Métricas de rendimiento",
+	"Network Diagnostics": "Diagnóstico de red",
+	"Instructor":          "Instructor",
+	"Unknown":             "Desconocida",
+	"Entering API key":    "Introduciendo la clave de API",
+	"Creating profile":    "Creando un perfil",
+	"Entering model path": "Introduciendo la ruta del modelo",
+
+	// Home
+	"Start Lesson": "Empezar lección",
+	"Sandbox Mode": "Modo sandbox",
+	"Challenges":   "Desafíos",
+	"Exit":         "Salir",
+	"An immersive terminal-based learning environment": "Un entorno de aprendizaje inmersivo en la terminal",
+	"Welcome to Mainframe, your gateway to mastering terminal commands\n" +
+		"and system administration through interactive learning.\n\n" +
+		"• Gamified lessons with progressive difficulty\n" +
+		"• Real-world scenarios in a safe environment\n" +
+		"• AI-powered guidance and assistance": "Bienvenido a Mainframe, tu puerta de entrada para dominar los comandos\n" +
+		"de la terminal y la administración de sistemas con aprendizaje interactivo.\n\n" +
+		"• Lecciones con juegos y dificultad progresiva\n" +
+		"• Escenarios reales en un entorno seguro\n" +
+		"• Orientación y ayuda con IA",
+
+	// Settings
+	"Profile":                    "Perfil",
+	"AI Model":                   "Modelo de IA",
+	"Theme":                      "Tema",
+	"Language":                   "Idioma",
+	"Back to Main Menu":          "Volver al menú principal",
+	"Settings Help":              "Ayuda de configuración",
+	"API Key Configuration":      "Configuración de la clave de API",
+	"Enter your OpenAI API key:": "Introduce tu clave de API de OpenAI:",
+	"Enter your API key":         "Introduce tu clave de API",
+	"New Profile":                "Nuevo perfil",
+	"The new profile starts as a copy of %q:": "El nuevo perfil empieza como una copia de %q:",
+	"Enter a name for the new profile":        "Introduce un nombre para el nuevo perfil",
+	"API key must be at least 32 characters":  "La clave de API debe tener al menos 32 caracteres",
+	"Invalid API key format":                  "Formato de clave de API no válido",
+	"Profiles":                                "Perfiles",
+	"Each profile keeps its own model, API key and developer settings.\n" +
+		"Switch profiles to move between setups without re-entering keys.": "Cada perfil guarda su propio modelo, clave de API y opciones de desarrollador.\n" +
+		"Cambia de perfil para pasar de una configuración a otra sin volver a introducir claves.",
+	"Press %s to switch to the next profile\n" +
+		"Press %s to create a new profile from the current one": "Pulsa %s para cambiar al siguiente perfil\n" +
+		"Pulsa %s para crear un perfil nuevo a partir del actual",
+	"Active Profile: %s": "Perfil activo: %s",
+	"AI Model Selection": "Selección del modelo de IA",
+	"Choose the AI model that powers your learning experience:\n\n" +
+		"• Local Model\n" +
+		"  Run models directly on your machine\n" +
+		"  Supports Llama 2, GPT-J, and BLOOM\n" +
+		"  Complete privacy and offline usage\n\n" +
+		"• GPT Model\n" +
+		"  Use OpenAI's powerful GPT models\n" +
+		"  Requires internet and API key\n" +
+		"  State-of-the-art performance": "Elige el modelo de IA que impulsa tu aprendizaje:\n\n" +
+		"• Modelo local\n" +
+		"  Ejecuta los modelos en tu propio equipo\n" +
+		"  Compatible con Llama 2, GPT-J y BLOOM\n" +
+		"  Privacidad total y uso sin conexión\n\n" +
+		"• Modelo GPT\n" +
+		"  Usa los potentes modelos GPT de OpenAI\n" +
+		"  Requiere internet y una clave de API\n" +
+		"  Rendimiento de vanguardia",
+	"Current Model: %s": "Modelo actual: %s",
+	"Local Model Setup": "Configuración del modelo local",
+	"Configure your local model installation:\n\n" +
+		"1. Download a compatible model\n" +
+		"2. Set up the model path\n" +
+		"3. Test the connection\n\n" +
+		"Press %s to start the setup process": "Configura la instalación de tu modelo local:\n\n" +
+		"1. Descarga un modelo compatible\n" +
+		"2. Indica la ruta del modelo\n" +
+		"3. Prueba la conexión\n\n" +
+		"Pulsa %s para empezar la configuración",
+	"OpenAI Configuration": "Configuración de OpenAI",
+	"Configure your OpenAI API access:\n\n" +
+		"• Set up your API key\n" +
+		"• Manage model preferences\n" +
+		"• Test API connectivity\n\n" +
+		"Press %s to configure your API key": "Configura tu acceso a la API de OpenAI:\n\n" +
+		"• Configura tu clave de API\n" +
+		"• Gestiona las preferencias del modelo\n" +
+		"• Prueba la conexión con la API\n\n" +
+		"Pulsa %s para configurar tu clave de API",
+	"Advanced settings for development and debugging:\n\n" +
+		"• Debug logging\n" +
+		"• Performance monitoring\n" +
+		"• Experimental features\n" +
+		"• Network diagnostics\n\n" +
+		"Press %s to access developer settings": "Opciones avanzadas de desarrollo y depuración:\n\n" +
+		"• Registro de depuración\n" +
+		"• Supervisión del rendimiento\n" +
+		"• Funciones experimentales\n" +
+		"• Diagnóstico de red\n\n" +
+		"Pulsa %s para abrir las opciones de desarrollador",
+	"Color Theme": "Tema de color",
+	"Change the colors used throughout Mainframe:": "Cambia los colores que usa Mainframe:",
+	"Custom themes are loaded from %s/*.toml":      "Los temas personalizados se cargan de %s/*.toml",
+	"Press %s to switch to the next theme":         "Pulsa %s para cambiar al siguiente tema",
+	"Current Theme: %s":                            "Tema actual: %s",
+	"Change the language of Mainframe. Automatic follows\n" +
+		"your system locale from LC_ALL, LC_MESSAGES or LANG.": "Cambia el idioma de Mainframe. Automático sigue la\n" +
+		"configuración regional del sistema en LC_ALL, LC_MESSAGES o LANG.",
+	"Automatic (%s)": "Automático (%s)",
+	"Press %s to switch to the next language": "Pulsa %s para cambiar al siguiente idioma",
+	"Current Language: %s":                    "Idioma actual: %s",
+
+	// Developer options
+	"Debug Mode":             "Modo de depuración",
+	"Log Output":             "Registro en archivo",
+	"View Logs":              "Ver registros",
+	"Back to Settings":       "Volver a la configuración",
+	"Developer Options Help": "Ayuda de opciones de desarrollador",
+	"Developer Tools":        "Herramientas de desarrollo",
+	"Toggle development features and debugging tools": "Activa o desactiva funciones de desarrollo y herramientas de depuración",
+	"Enable detailed debug information and error reporting for troubleshooting. Press %s on any screen to show the debug overlay": "Activa información detallada de depuración e informes de errores para resolver problemas. Pulsa %s en cualquier pantalla para ver el panel de depuración",
+	"Save detailed logs to %s for system analysis":                                              "Guarda registros detallados en %s para analizar el sistema",
+	"Turn individual experimental features on or off, or all of them at once (may be unstable)": "Activa o desactiva funciones experimentales una a una o todas a la vez (pueden ser inestables)",
+	"Monitor system performance, memory usage, and resource allocation":                         "Supervisa el rendimiento del sistema, el uso de memoria y la asignación de recursos",
+	"Test network connectivity and API endpoint responsiveness":                                 "Prueba la conectividad de red y la respuesta del servicio de la API",
+	"Read, search and follow the logs in %s":                                                    "Lee, busca y sigue los registros de %s",
+	"Return to the settings menu":                                                               "Vuelve al menú de configuración",
+	"System Information":                                                                        "Información del sistema",
+	"Config Path: %s":                                                                           "Ruta de configuración: %s",
+	"Log Path: %s":                                                                              "Ruta de registros: %s",
+	"Debug Level: %s":                                                                           "Nivel de depuración: %s",
+	"VERBOSE":                                                                                   "DETALLADO",
+	"NORMAL":                                                                                    "NORMAL",
+	"Status Dashboard":                                                                          "Panel de estado",
+	"Experimental Mode":                                                                         "Modo experimental",
+	"Performance Monitor":                                                                       "Monitor de rendimiento",
+
+	// Feature flags
+	"All Experimental Flags":    "Todas las funciones experimentales",
+	"Back to Developer Options": "Volver a opciones de desarrollador",
+	"Flag":                      "Función",
+	"Name:      %s\nStability: %s\nDefault:   %s\nExpires:   %s\nState:     %s":                        "Nombre:       %s\nEstabilidad:  %s\nPor defecto:  %s\nCaduca:       %s\nEstado:       %s",
+	"Turn on every experimental and beta flag at once. Flags set individually keep their own setting.": "Activa a la vez todas las funciones experimentales y beta. Las que tengan un valor propio lo conservan.",
+	"Return to the developer options":               "Vuelve a las opciones de desarrollador",
+	"%s has expired and always follows its default": "%s ha caducado y siempre usa su valor predeterminado",
+	"Error saving config: %v":                       "Error al guardar la configuración: %v",
+	"expired, following its default":                "caducada, usa su valor predeterminado",
+	"set in profile %s":                             "definida en el perfil %s",
+	"on with all experimental flags":                "activada con todas las funciones experimentales",
+
+	// Model configuration
+	"1. Download Model":                    "1. Descargar modelo",
+	"2. Configure Model Path":              "2. Configurar ruta del modelo",
+	"3. Test Model":                        "3. Probar modelo",
+	"Please complete previous steps first": "Completa antes los pasos anteriores",
+	"Local Model Setup Help":               "Ayuda de configuración del modelo local",
+	"Steps to setup your local model:\n\n" +
+		"1. Download a compatible model (e.g., Llama2)\n" +
+		"2. Configure the path to model weights\n" +
+		"3. Test the model connection": "Pasos para configurar tu modelo local:\n\n" +
+		"1. Descarga un modelo compatible (p. ej., Llama2)\n" +
+		"2. Configura la ruta de los pesos del modelo\n" +
+		"3. Prueba la conexión con el modelo",
+	"Configure Model Path":                     "Configurar ruta del modelo",
+	"Enter the path to your model weights:":    "Introduce la ruta de los pesos de tu modelo:",
+	"Enter path to model weights":              "Ruta de los pesos del modelo",
+	"Setup Steps":                              "Pasos de configuración",
+	"Download Compatible Model":                "Descargar un modelo compatible",
+	"Choose and download one of these models:": "Elige y descarga uno de estos modelos:",
+	"Size: %s parameters":                      "Tamaño: %s parámetros",
+	"License: %s":                              "Licencia: %s",
+	"Model Path Configuration":                 "Configuración de la ruta del modelo",
+	"Set up the path to your downloaded model:\n\n" +
+		"1. Locate your downloaded model files\n" +
+		"2. Copy the full path to the weights file\n" +
+		"3. Press %s to open the path input\n" +
+		"4. Paste or type the path\n\n" +
+		"Example paths:": "Indica la ruta del modelo que has descargado:\n\n" +
+		"1. Busca los archivos del modelo descargado\n" +
+		"2. Copia la ruta completa del archivo de pesos\n" +
+		"3. Pulsa %s para abrir el campo de la ruta\n" +
+		"4. Pega o escribe la ruta\n\n" +
+		"Rutas de ejemplo:",
+	"Model Testing": "Prueba del modelo",
+	"Verify your model configuration:\n\n" +
+		"• Check model file accessibility\n" +
+		"• Validate model format\n" +
+		"• Test basic inference\n" +
+		"• Measure performance": "Verifica la configuración de tu modelo:\n\n" +
+		"• Comprueba el acceso al archivo del modelo\n" +
+		"• Valida el formato del modelo\n" +
+		"• Prueba una inferencia básica\n" +
+		"• Mide el rendimiento",
+	"Server: %s":     "Servidor: %s",
+	"Status: %s":     "Estado: %s",
+	"Setup Progress": "Progreso de la configuración",
+	"TESTING...":     "PROBANDO...",
+	"PASSED":         "CORRECTO",
+	"FAILED":         "FALLIDO",
+	"NOT READY":      "NO PREPARADO",
+	"READY TO TEST":  "LISTO PARA PROBAR",
+	"COMPLETED":      "COMPLETADO",
+	"IN PROGRESS":    "EN CURSO",
+	"NOT STARTED":    "SIN EMPEZAR",
+
+	// Command palette
+	"Command Palette": "Paleta de comandos",
+	"Type to search screens, toggles and actions": "Escribe para buscar pantallas, opciones y acciones",
+	"No matches":        "Sin resultados",
+	"screen":            "pantalla",
+	"action":            "acción",
+	"Experimental":      "Experimental",
+	"Accessible Mode":   "Modo accesible",
+	"Test Model":        "Probar modelo",
+	"Configure API Key": "Configurar clave de API",
+	"Quit":              "Salir",
+
+	// Config conflicts and broadcasts
+	"Config Changed On Disk": "La configuración cambió en el disco",
+	"%s was edited outside Mainframe\n" +
+		"while these settings had unsaved changes:": "%s se editó fuera de Mainframe\n" +
+		"mientras estas opciones tenían cambios sin guardar:",
+	"• %s\n    mine:   %s\n    theirs: %s\n": "• %s\n    míos:      %s\n    del disco: %s\n",
+	"Message From %s":                        "Mensaje de %s",
+	"Sent %s • press any key to continue":    "Enviado a las %s • pulsa cualquier tecla para continuar",
+
+	// Performance metrics
+	"Process":              "Proceso",
+	"Rendering":            "Renderizado",
+	"Host":                 "Equipo",
+	"Update Latency":       "Latencia de actualización",
+	"RSS":                  "RSS",
+	"Heap":                 "Heap",
+	"Goroutines":           "Goroutines",
+	"GC pause":             "Pausa de GC",
+	"GC cycles":            "Ciclos de GC",
+	"Frame time":           "Tiempo/frame",
+	"Frames":               "Frames",
+	"Slowest":              "Más lento",
+	"CPU":                  "CPU",
+	"Memory":               "Memoria",
+	"MESSAGE":              "MENSAJE",
+	"COUNT":                "CANTIDAD",
+	"MEAN":                 "MEDIA",
+	"MAX":                  "MÁX",
+	"sampling every %s • ": "muestreo cada %s • ",
+
+	// Network diagnostics
+	"OpenAI API":                "API de OpenAI",
+	"local model server":        "servidor del modelo local",
+	"Endpoint: %s (%s)":         "Servicio: %s (%s)",
+	"Running checks...":         "Ejecutando comprobaciones...",
+	"Proxy":                     "Proxy",
+	"none, connecting directly": "ninguno, conexión directa",
+	"HTTP_PROXY:  %s\nHTTPS_PROXY: %s\nNO_PROXY:    %s\nUsed:        %s\n": "HTTP_PROXY:  %s\nHTTPS_PROXY: %s\nNO_PROXY:    %s\nEn uso:      %s\n",
+
+	// Logs
+	"No entries match the current filters":                        "Ninguna entrada coincide con los filtros actuales",
+	"No logs yet. Turn on Log Output to start writing them to %s": "Aún no hay registros. Activa el Registro en archivo para empezar a escribirlos en %s",
+	"Search":                              "Buscar",
+	"15:04, 15:04:05 or 2006-01-02 15:04": "15:04, 15:04:05 o 2006-01-02 15:04",
+	"%s ago":                              "hace %s",
+	"cannot read %q as a time":            "no se puede leer %q como una hora",
+	"(live)":                              "(en directo)",
+	"File: %s  •  Level ≥ %s  •  Component: %s  •  %d/%d entries": "Archivo: %s  •  Nivel ≥ %s  •  Componente: %s  •  %d/%d entradas",
+	"  •  %q: %d matches": "  •  %q: %d coincidencias",
+
+	// Instructor
+	"Broadcast":                       "Difusión",
+	"Message for every trainee":       "Mensaje para todos los alumnos",
+	"Pops up in %d trainee sessions:": "Aparecerá en %d sesiones de alumnos:",
+	"%s%-12s %-20s idle %s":           "%s%-12s %-20s inactivo %s",
+	"Nobody is online":                "No hay nadie conectado",
+	"Trainees (%d)":                   "Alumnos (%d)",
+	"Screen:   %s\nActivity: %s\nIdle:     %s\nOnline:   %s\nProcess:  %s":          "Pantalla:   %s\nActividad:  %s\nInactivo:   %s\nConectado:  %s\nProceso:    %s",
+	"Trainees appear here while Mainframe is running\nwith presence_dir set to %s.": "Los alumnos aparecen aquí mientras Mainframe se ejecuta\ncon presence_dir en %s.",
+	"Last broadcast at %s: %s": "Última difusión a las %s: %s",
+	"presence_dir is not set, so trainees are not publishing heartbeats": "presence_dir no está definido, así que los alumnos no publican su estado",
+
+	// Debug overlay
+	"%s to hide":           "%s para ocultar",
+	"Messages":             "Mensajes",
+	"Config Since Startup": "Configuración desde el inicio",
+	"no changes":           "sin cambios",
+	"Recent Errors":        "Errores recientes",
+}
@@ -0,0 +1,143 @@
+// Package i18n translates the text of the TUI. Messages are looked up by
+// their English text, which is also the format string, so text without a
+// translation shows in English:
+//
+//	l := i18n.New("es")
+//	l.Sprintf("lines %d-%d of %d", 1, 20, 140) // "líneas 1-20 de 140"
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Auto picks the language from the environment, see Detect
+const Auto = "auto"
+
+// languages are the supported languages, English first as the fallback
+var languages = []struct {
+	code string
+	name string
+	tag  language.Tag
+	msgs map[string]string
+}{
+	{"en", "English", language.English, nil},
+	{"es", "Español", language.Spanish, spanish},
+	{"pt", "Português", language.Portuguese, portuguese},
+}
+
+var (
+	catalogs = catalog.NewBuilder(catalog.Fallback(language.English))
+	matcher  language.Matcher
+)
+
+func init() {
+	tags := make([]language.Tag, len(languages))
+	for i, l := range languages {
+		tags[i] = l.tag
+		for key, msg := range l.msgs {
+			catalogs.SetString(l.tag, key, msg)
+		}
+	}
+	matcher = language.NewMatcher(tags)
+}
+
+// Languages lists the codes of the supported languages, English first
+func Languages() []string {
+	codes := make([]string, len(languages))
+	for i, l := range languages {
+		codes[i] = l.code
+	}
+	return codes
+}
+
+// Name returns the name of the language with the given code in that
+// language, or the code itself if it is not supported
+func Name(code string) string {
+	for _, l := range languages {
+		if l.code == code {
+			return l.name
+		}
+	}
+	return code
+}
+
+// Detect returns the supported language closest to the user's locale,
+// read from LC_ALL, LC_MESSAGES and LANG in that order as POSIX does.
+// Locales it cannot match, and the C locale, give English.
+func Detect() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			return match(v)
+		}
+	}
+	return languages[0].code
+}
+
+// match finds the supported language of a POSIX locale such as
+// pt_BR.UTF-8 or es_ES@euro
+func match(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	locale, _, _ = strings.Cut(locale, "@")
+	tag, err := language.Parse(strings.ReplaceAll(locale, "_", "-"))
+	if err != nil {
+		return languages[0].code
+	}
+	_, i, confidence := matcher.Match(tag)
+	if confidence == language.No {
+		return languages[0].code
+	}
+	return languages[i].code
+}
+
+// Locale formats text in one language. The zero value and nil format in
+// English.
+type Locale struct {
+	code    string
+	printer *message.Printer
+}
+
+// New returns a locale for the language with the given code, or for the
+// detected language when code is empty or Auto. Unsupported codes give
+// English.
+func New(code string) *Locale {
+	l := &Locale{}
+	l.Set(code)
+	return l
+}
+
+// Set switches l to another language, as New picks it
+func (l *Locale) Set(code string) {
+	if code == "" || code == Auto {
+		code = Detect()
+	}
+	l.code, l.printer = languages[0].code, nil
+	for _, lang := range languages[1:] {
+		if lang.code == code {
+			l.code = code
+			l.printer = message.NewPrinter(lang.tag, message.Catalog(catalogs))
+		}
+	}
+}
+
+// Code returns the code of the language l formats in
+func (l *Locale) Code() string {
+	if l == nil || l.code == "" {
+		return languages[0].code
+	}
+	return l.code
+}
+
+// Sprintf translates format and formats it with args. English prints as
+// fmt does, other languages also use their own number formatting.
+func (l *Locale) Sprintf(format string, args ...any) string {
+	if l == nil || l.printer == nil {
+		return fmt.Sprintf(format, args...)
+	}
+	return l.printer.Sprintf(format, args...)
+}
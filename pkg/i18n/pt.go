@@ -0,0 +1,363 @@
+package i18n
+
+// portuguese translates the TUI into Portuguese
+var portuguese = map[string]string{
+	// Shared
+	"on":                       "ligado",
+	"off":                      "desligado",
+	"ON":                       "LIGADO",
+	"OFF":                      "DESLIGADO",
+	"(selected)":               "(selecionado)",
+	"(unset)":                  "(não definido)",
+	"(none)":                   "(nenhum)",
+	"none":                     "nenhum",
+	"default":                  "padrão",
+	"never":                    "nunca",
+	"all":                      "todos",
+	"n/a":                      "n/d",
+	"Error":                    "Erro",
+	"Screen":                   "Tela",
+	"Selected":                 "Selecionado",
+	"%d of %d on":              "%d de %d ligados",
+	"[ACTIVE]":                 "[ATIVO]",
+	"[INACTIVE]":               "[INATIVO]",
+	"AVAILABLE":                "DISPONÍVEL",
+	"lines %d-%d of %d • %d%%": "linhas %d-%d de %d • %d%%",
+
+	// Key help
+	"up":                                 "subir",
+	"down":                               "descer",
+	"top":                                "início",
+	"bottom":                             "fim",
+	"select":                             "escolher",
+	"back":                               "voltar",
+	"help":                               "ajuda",
+	"quit":                               "sair",
+	"debug overlay":                      "painel de depuração",
+	"command palette":                    "paleta de comandos",
+	"switch panel":                       "trocar painel",
+	"page up":                            "página acima",
+	"page down":                          "página abaixo",
+	"save":                               "salvar",
+	"cancel":                             "cancelar",
+	"merge":                              "mesclar",
+	"override with mine":                 "usar os meus",
+	"take theirs":                        "usar os do disco",
+	"new profile":                        "novo perfil",
+	"refresh":                            "atualizar",
+	"broadcast":                          "transmitir",
+	"sample faster":                      "amostrar mais rápido",
+	"sample slower":                      "amostrar mais devagar",
+	"reset":                              "redefinir",
+	"search":                             "buscar",
+	"next match":                         "próxima ocorrência",
+	"previous match":                     "ocorrência anterior",
+	"jump to time":                       "ir para horário",
+	"level":                              "nível",
+	"component":                          "componente",
+	"older file":                         "arquivo anterior",
+	"newer file":                         "arquivo seguinte",
+	"move":                               "mover",
+	"go":                                 "ir",
+	"close":                              "fechar",
+	"close help":                         "fechar ajuda",
+	"create":                             "criar",
+	"send":                               "enviar",
+	"toggle":                             "alternar",
+	"toggle or open":                     "alternar ou abrir",
+	"run again":                          "executar de novo",
+	"back to main menu":                  "voltar ao menu principal",
+	"back to settings":                   "voltar às configurações",
+	"new profile (on Profile)":           "novo perfil (em Perfil)",
+	"debug overlay (with Debug Mode on)": "painel de depuração (com o Modo de depuração ligado)",
+
+	// Screens
+	"Home":                "Início",
+	"Settings":            "Configurações",
+	"Developer Options":   "Opções de desenvolvedor",
+	"Feature Flags":       "Recursos experimentais",
+	"Model Configuration": "Configuração do modelo",
+	"Logs":                "Logs",
+	"Performance Metrics": "Métricas de desempenho",
+	"Network Diagnostics": "Diagnóstico de rede",
+	"Instructor":          "Instrutor",
+	"Unknown":             "Desconhecida",
+	"Entering API key":    "Digitando a chave de API",
+	"Creating profile":    "Criando um perfil",
+	"Entering model path": "Digitando o caminho do modelo",
+
+	// Home
+	"Start Lesson": "Começar lição",
+	"Sandbox Mode": "Modo sandbox",
+	"Challenges":   "Desafios",
+	"Exit":         "Sair",
+	"An immersive terminal-based learning environment": "Um ambiente de aprendizado imersivo no terminal",
+	"Welcome to Mainframe, your gateway to mastering terminal commands\n" +
+		"and system administration through interactive learning.\n\n" +
+		"• Gamified lessons with progressive difficulty\n" +
+		"• Real-world scenarios in a safe environment\n" +
+		"• AI-powered guidance and assistance": "Bem-vindo ao Mainframe, seu caminho para dominar os comandos do\n" +
+		"terminal e a administração de sistemas com aprendizado interativo.\n\n" +
+		"• Lições gamificadas com dificuldade progressiva\n" +
+		"• Cenários reais em um ambiente seguro\n" +
+		"• Orientação e ajuda com IA",
+
+	// Settings
+	"Profile":                    "Perfil",
+	"AI Model":                   "Modelo de IA",
+	"Theme":                      "Tema",
+	"Language":                   "Idioma",
+	"Back to Main Menu":          "Voltar ao menu principal",
+	"Settings Help":              "Ajuda das configurações",
+	"API Key Configuration":      "Configuração da chave de API",
+	"Enter your OpenAI API key:": "Digite sua chave de API da OpenAI:",
+	"Enter your API key":         "Digite sua chave de API",
+	"New Profile":                "Novo perfil",
+	"The new profile starts as a copy of %q:": "O novo perfil começa como uma cópia de %q:",
+	"Enter a name for the new profile":        "Digite um nome para o novo perfil",
+	"API key must be at least 32 characters":  "A chave de API precisa ter pelo menos 32 caracteres",
+	"Invalid API key format":                  "Formato de chave de API inválido",
+	"Profiles":                                "Perfis",
+	"Each profile keeps its own model, API key and developer settings.\n" +
+		"Switch profiles to move between setups without re-entering keys.": "Cada perfil guarda seu próprio modelo, chave de API e opções de desenvolvedor.\n" +
+		"Troque de perfil para alternar entre configurações sem digitar as chaves de novo.",
+	"Press %s to switch to the next profile\n" +
+		"Press %s to create a new profile from the current one": "Pressione %s para trocar para o próximo perfil\n" +
+		"Pressione %s para criar um perfil novo a partir do atual",
+	"Active Profile: %s": "Perfil ativo: %s",
+	"AI Model Selection": "Escolha do modelo de IA",
+	"Choose the AI model that powers your learning experience:\n\n" +
+		"• Local Model\n" +
+		"  Run models directly on your machine\n" +
+		"  Supports Llama 2, GPT-J, and BLOOM\n" +
+		"  Complete privacy and offline usage\n\n" +
+		"• GPT Model\n" +
+		"  Use OpenAI's powerful GPT models\n" +
+		"  Requires internet and API key\n" +
+		"  State-of-the-art performance": "Escolha o modelo de IA que move o seu aprendizado:\n\n" +
+		"• Modelo local\n" +
+		"  Executa os modelos na sua própria máquina\n" +
+		"  Compatível com Llama 2, GPT-J e BLOOM\n" +
+		"  Privacidade total e uso offline\n\n" +
+		"• Modelo GPT\n" +
+		"  Usa os poderosos modelos GPT da OpenAI\n" +
+		"  Requer internet e uma chave de API\n" +
+		"  Desempenho de ponta",
+	"Current Model: %s": "Modelo atual: %s",
+	"Local Model Setup": "Configuração do modelo local",
+	"Configure your local model installation:\n\n" +
+		"1. Download a compatible model\n" +
+		"2. Set up the model path\n" +
+		"3. Test the connection\n\n" +
+		"Press %s to start the setup process": "Configure a instalação do seu modelo local:\n\n" +
+		"1. Baixe um modelo compatível\n" +
+		"2. Informe o caminho do modelo\n" +
+		"3. Teste a conexão\n\n" +
+		"Pressione %s para começar a configuração",
+	"OpenAI Configuration": "Configuração da OpenAI",
+	"Configure your OpenAI API access:\n\n" +
+		"• Set up your API key\n" +
+		"• Manage model preferences\n" +
+		"• Test API connectivity\n\n" +
+		"Press %s to configure your API key": "Configure seu acesso à API da OpenAI:\n\n" +
+		"• Configure sua chave de API\n" +
+		"• Gerencie as preferências do modelo\n" +
+		"• Teste a conexão com a API\n\n" +
+		"Pressione %s para configurar sua chave de API",
+	"Advanced settings for development and debugging:\n\n" +
+		"• Debug logging\n" +
+		"• Performance monitoring\n" +
+		"• Experimental features\n" +
+		"• Network diagnostics\n\n" +
+		"Press %s to access developer settings": "Opções avançadas de desenvolvimento e depuração:\n\n" +
+		"• Logs de depuração\n" +
+		"• Monitoramento de desempenho\n" +
+		"• Recursos experimentais\n" +
+		"• Diagnóstico de rede\n\n" +
+		"Pressione %s para abrir as opções de desenvolvedor",
+	"Color Theme": "Tema de cores",
+	"Change the colors used throughout Mainframe:": "Mude as cores usadas no Mainframe:",
+	"Custom themes are loaded from %s/*.toml":      "Temas personalizados são carregados de %s/*.toml",
+	"Press %s to switch to the next theme":         "Pressione %s para trocar para o próximo tema",
+	"Current Theme: %s":                            "Tema atual: %s",
+	"Change the language of Mainframe. Automatic follows\n" +
+		"your system locale from LC_ALL, LC_MESSAGES or LANG.": "Mude o idioma do Mainframe. Automático segue a\n" +
+		"localidade do sistema em LC_ALL, LC_MESSAGES ou LANG.",
+	"Automatic (%s)": "Automático (%s)",
+	"Press %s to switch to the next language": "Pressione %s para trocar para o próximo idioma",
+	"Current Language: %s":                    "Idioma atual: %s",
+
+	// Developer options
+	"Debug Mode":             "Modo de depuração",
+	"Log Output":             "Gravação de logs",
+	"View Logs":              "Ver logs",
+	"Back to Settings":       "Voltar às configurações",
+	"Developer Options Help": "Ajuda das opções de desenvolvedor",
+	"Developer Tools":        "Ferramentas de desenvolvimento",
+	"Toggle development features and debugging tools": "Ligue ou desligue recursos de desenvolvimento e ferramentas de depuração",
+	"Enable detailed debug information and error reporting for troubleshooting. Press %s on any screen to show the debug overlay": "Ativa informações detalhadas de depuração e relatórios de erros para resolver problemas. Pressione %s em qualquer tela para ver o painel de depuração",
+	"Save detailed logs to %s for system analysis":                                              "Grava logs detalhados em %s para análise do sistema",
+	"Turn individual experimental features on or off, or all of them at once (may be unstable)": "Liga ou desliga recursos experimentais um a um ou todos de uma vez (podem ser instáveis)",
+	"Monitor system performance, memory usage, and resource allocation":                         "Acompanha o desempenho do sistema, o uso de memória e a alocação de recursos",
+	"Test network connectivity and API endpoint responsiveness":                                 "Testa a conectividade de rede e a resposta do serviço da API",
+	"Read, search and follow the logs in %s":                                                    "Lê, busca e acompanha os logs em %s",
+	"Return to the settings menu":                                                               "Volta ao menu de configurações",
+	"System Information":                                                                        "Informações do sistema",
+	"Config Path: %s":                                                                           "Caminho da configuração: %s",
+	"Log Path: %s":                                                                              "Caminho dos logs: %s",
+	"Debug Level: %s":                                                                           "Nível de depuração: %s",
+	"VERBOSE":                                                                                   "DETALHADO",
+	"NORMAL":                                                                                    "NORMAL",
+	"Status Dashboard":                                                                          "Painel de status",
+	"Experimental Mode":                                                                         "Modo experimental",
+	"Performance Monitor":                                                                       "Monitor de desempenho",
+
+	// Feature flags
+	"All Experimental Flags":    "Todos os recursos experimentais",
+	"Back to Developer Options": "Voltar às opções de desenvolvedor",
+	"Flag":                      "Recurso",
+	"Name:      %s\nStability: %s\nDefault:   %s\nExpires:   %s\nState:     %s":                        "Nome:          %s\nEstabilidade:  %s\nPadrão:        %s\nExpira:        %s\nEstado:        %s",
+	"Turn on every experimental and beta flag at once. Flags set individually keep their own setting.": "Liga de uma vez todos os recursos experimentais e beta. Os que têm valor próprio mantêm esse valor.",
+	"Return to the developer options":               "Volta às opções de desenvolvedor",
+	"%s has expired and always follows its default": "%s expirou e sempre segue o seu padrão",
+	"Error saving config: %v":                       "Erro ao salvar a configuração: %v",
+	"expired, following its default":                "expirado, segue o padrão",
+	"set in profile %s":                             "definido no perfil %s",
+	"on with all experimental flags":                "ligado com todos os recursos experimentais",
+
+	// Model configuration
+	"1. Download Model":                    "1. Baixar modelo",
+	"2. Configure Model Path":              "2. Configurar caminho do modelo",
+	"3. Test Model":                        "3. Testar modelo",
+	"Please complete previous steps first": "Conclua primeiro as etapas anteriores",
+	"Local Model Setup Help":               "Ajuda da configuração do modelo local",
+	"Steps to setup your local model:\n\n" +
+		"1. Download a compatible model (e.g., Llama2)\n" +
+		"2. Configure the path to model weights\n" +
+		"3. Test the model connection": "Etapas para configurar seu modelo local:\n\n" +
+		"1. Baixe um modelo compatível (ex.: Llama2)\n" +
+		"2. Configure o caminho dos pesos do modelo\n" +
+		"3. Teste a conexão com o modelo",
+	"Configure Model Path":                     "Configurar caminho do modelo",
+	"Enter the path to your model weights:":    "Digite o caminho dos pesos do seu modelo:",
+	"Enter path to model weights":              "Caminho dos pesos do modelo",
+	"Setup Steps":                              "Etapas da configuração",
+	"Download Compatible Model":                "Baixar um modelo compatível",
+	"Choose and download one of these models:": "Escolha e baixe um destes modelos:",
+	"Size: %s parameters":                      "Tamanho: %s parâmetros",
+	"License: %s":                              "Licença: %s",
+	"Model Path Configuration":                 "Configuração do caminho do modelo",
+	"Set up the path to your downloaded model:\n\n" +
+		"1. Locate your downloaded model files\n" +
+		"2. Copy the full path to the weights file\n" +
+		"3. Press %s to open the path input\n" +
+		"4. Paste or type the path\n\n" +
+		"Example paths:": "Informe o caminho do modelo que você baixou:\n\n" +
+		"1. Localize os arquivos do modelo baixado\n" +
+		"2. Copie o caminho completo do arquivo de pesos\n" +
+		"3. Pressione %s para abrir o campo do caminho\n" +
+		"4. Cole ou digite o caminho\n\n" +
+		"Caminhos de exemplo:",
+	"Model Testing": "Teste do modelo",
+	"Verify your model configuration:\n\n" +
+		"• Check model file accessibility\n" +
+		"• Validate model format\n" +
+		"• Test basic inference\n" +
+		"• Measure performance": "Verifique a configuração do seu modelo:\n\n" +
+		"• Confere o acesso ao arquivo do modelo\n" +
+		"• Valida o formato do modelo\n" +
+		"• Testa uma inferência básica\n" +
+		"• Mede o desempenho",
+	"Server: %s":     "Servidor: %s",
+	"Status: %s":     "Status: %s",
+	"Setup Progress": "Progresso da configuração",
+	"TESTING...":     "TESTANDO...",
+	"PASSED":         "APROVADO",
+	"FAILED":         "FALHOU",
+	"NOT READY":      "NÃO PRONTO",
+	"READY TO TEST":  "PRONTO PARA TESTAR",
+	"COMPLETED":      "CONCLUÍDO",
+	"IN PROGRESS":    "EM ANDAMENTO",
+	"NOT STARTED":    "NÃO INICIADO",
+
+	// Command palette
+	"Command Palette": "Paleta de comandos",
+	"Type to search screens, toggles and actions": "Digite para buscar telas, opções e ações",
+	"No matches":        "Nenhum resultado",
+	"screen":            "tela",
+	"action":            "ação",
+	"Experimental":      "Experimental",
+	"Accessible Mode":   "Modo acessível",
+	"Test Model":        "Testar modelo",
+	"Configure API Key": "Configurar chave de API",
+	"Quit":              "Sair",
+
+	// Config conflicts and broadcasts
+	"Config Changed On Disk": "A configuração mudou no disco",
+	"%s was edited outside Mainframe\n" +
+		"while these settings had unsaved changes:": "%s foi editado fora do Mainframe\n" +
+		"enquanto estas opções tinham alterações não salvas:",
+	"• %s\n    mine:   %s\n    theirs: %s\n": "• %s\n    meus:     %s\n    do disco: %s\n",
+	"Message From %s":                        "Mensagem de %s",
+	"Sent %s • press any key to continue":    "Enviada às %s • pressione qualquer tecla para continuar",
+
+	// Performance metrics
+	"Process":              "Processo",
+	"Rendering":            "Renderização",
+	"Host":                 "Máquina",
+	"Update Latency":       "Latência de atualização",
+	"RSS":                  "RSS",
+	"Heap":                 "Heap",
+	"Goroutines":           "Goroutines",
+	"GC pause":             "Pausa do GC",
+	"GC cycles":            "Ciclos do GC",
+	"Frame time":           "Tempo/frame",
+	"Frames":               "Frames",
+	"Slowest":              "Mais lento",
+	"CPU":                  "CPU",
+	"Memory":               "Memória",
+	"MESSAGE":              "MENSAGEM",
+	"COUNT":                "QTD",
+	"MEAN":                 "MÉDIA",
+	"MAX":                  "MÁX",
+	"sampling every %s • ": "amostragem a cada %s • ",
+
+	// Network diagnostics
+	"OpenAI API":                "API da OpenAI",
+	"local model server":        "servidor do modelo local",
+	"Endpoint: %s (%s)":         "Serviço: %s (%s)",
+	"Running checks...":         "Executando verificações...",
+	"Proxy":                     "Proxy",
+	"none, connecting directly": "nenhum, conexão direta",
+	"HTTP_PROXY:  %s\nHTTPS_PROXY: %s\nNO_PROXY:    %s\nUsed:        %s\n": "HTTP_PROXY:  %s\nHTTPS_PROXY: %s\nNO_PROXY:    %s\nEm uso:      %s\n",
+
+	// Logs
+	"No entries match the current filters":                        "Nenhuma entrada corresponde aos filtros atuais",
+	"No logs yet. Turn on Log Output to start writing them to %s": "Ainda não há logs. Ligue a Gravação de logs para começar a gravá-los em %s",
+	"Search":                              "Buscar",
+	"15:04, 15:04:05 or 2006-01-02 15:04": "15:04, 15:04:05 ou 2006-01-02 15:04",
+	"%s ago":                              "há %s",
+	"cannot read %q as a time":            "não é possível ler %q como horário",
+	"(live)":                              "(ao vivo)",
+	"File: %s  •  Level ≥ %s  •  Component: %s  •  %d/%d entries": "Arquivo: %s  •  Nível ≥ %s  •  Componente: %s  •  %d/%d entradas",
+	"  •  %q: %d matches": "  •  %q: %d ocorrências",
+
+	// Instructor
+	"Broadcast":                       "Transmissão",
+	"Message for every trainee":       "Mensagem para todos os alunos",
+	"Pops up in %d trainee sessions:": "Aparece em %d sessões de alunos:",
+	"%s%-12s %-20s idle %s":           "%s%-12s %-20s inativo %s",
+	"Nobody is online":                "Ninguém está online",
+	"Trainees (%d)":                   "Alunos (%d)",
+	"Screen:   %s\nActivity: %s\nIdle:     %s\nOnline:   %s\nProcess:  %s":          "Tela:       %s\nAtividade:  %s\nInativo:    %s\nOnline:     %s\nProcesso:   %s",
+	"Trainees appear here while Mainframe is running\nwith presence_dir set to %s.": "Os alunos aparecem aqui enquanto o Mainframe está em execução\ncom presence_dir definido como %s.",
+	"Last broadcast at %s: %s": "Última transmissão às %s: %s",
+	"presence_dir is not set, so trainees are not publishing heartbeats": "presence_dir não está definido, então os alunos não publicam o seu status",
+
+	// Debug overlay
+	"%s to hide":           "%s para ocultar",
+	"Messages":             "Mensagens",
+	"Config Since Startup": "Configuração desde o início",
+	"no changes":           "sem alterações",
+	"Recent Errors":        "Erros recentes",
+}
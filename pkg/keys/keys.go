@@ -0,0 +1,347 @@
+// Package keys holds the key bindings of the TUI. Bindings start from a
+// preset and can be changed per action in a keys.toml file:
+//
+//	preset = "emacs"
+//
+//	[bindings]
+//	quit = ["ctrl+c"]
+//	search = ["ctrl+s", "/"]
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// DefaultPreset is used when keys.toml does not exist or names no preset
+const DefaultPreset = "vim"
+
+// KeyMap is every action the TUI binds a key to. Screens only look at the
+// actions they offer, so one key can mean different things on different
+// screens.
+type KeyMap struct {
+	// Navigation shared by every screen
+	Up     key.Binding
+	Down   key.Binding
+	Top    key.Binding
+	Bottom key.Binding
+	Select key.Binding
+	Back   key.Binding
+	Help   key.Binding
+	Quit   key.Binding
+	Debug  key.Binding
+	// Palette opens the command palette from any screen
+	Palette key.Binding
+
+	// Content panels
+	Focus    key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+
+	// Text inputs
+	Confirm key.Binding
+	Cancel  key.Binding
+
+	// Config conflicts
+	Merge  key.Binding
+	Mine   key.Binding
+	Theirs key.Binding
+
+	// Screen actions
+	NewProfile key.Binding
+	ResetFlag  key.Binding
+	Refresh    key.Binding
+	Broadcast  key.Binding
+	Faster     key.Binding
+	Slower     key.Binding
+	Reset      key.Binding
+
+	// Log viewer
+	Search    key.Binding
+	NextMatch key.Binding
+	PrevMatch key.Binding
+	JumpTime  key.Binding
+	Level     key.Binding
+	Component key.Binding
+	OlderFile key.Binding
+	NewerFile key.Binding
+}
+
+// action ties a binding to its name in keys.toml and its help text
+type action struct {
+	name    string
+	desc    string
+	binding func(*KeyMap) *key.Binding
+}
+
+var actions = []action{
+	{"up", "up", func(k *KeyMap) *key.Binding { return &k.Up }},
+	{"down", "down", func(k *KeyMap) *key.Binding { return &k.Down }},
+	{"top", "top", func(k *KeyMap) *key.Binding { return &k.Top }},
+	{"bottom", "bottom", func(k *KeyMap) *key.Binding { return &k.Bottom }},
+	{"select", "select", func(k *KeyMap) *key.Binding { return &k.Select }},
+	{"back", "back", func(k *KeyMap) *key.Binding { return &k.Back }},
+	{"help", "help", func(k *KeyMap) *key.Binding { return &k.Help }},
+	{"quit", "quit", func(k *KeyMap) *key.Binding { return &k.Quit }},
+	{"debug", "debug overlay", func(k *KeyMap) *key.Binding { return &k.Debug }},
+	{"palette", "command palette", func(k *KeyMap) *key.Binding { return &k.Palette }},
+	{"focus", "switch panel", func(k *KeyMap) *key.Binding { return &k.Focus }},
+	{"page_up", "page up", func(k *KeyMap) *key.Binding { return &k.PageUp }},
+	{"page_down", "page down", func(k *KeyMap) *key.Binding { return &k.PageDown }},
+	{"confirm", "save", func(k *KeyMap) *key.Binding { return &k.Confirm }},
+	{"cancel", "cancel", func(k *KeyMap) *key.Binding { return &k.Cancel }},
+	{"merge", "merge", func(k *KeyMap) *key.Binding { return &k.Merge }},
+	{"mine", "override with mine", func(k *KeyMap) *key.Binding { return &k.Mine }},
+	{"theirs", "take theirs", func(k *KeyMap) *key.Binding { return &k.Theirs }},
+	{"new_profile", "new profile", func(k *KeyMap) *key.Binding { return &k.NewProfile }},
+	{"reset_flag", "default", func(k *KeyMap) *key.Binding { return &k.ResetFlag }},
+	{"refresh", "refresh", func(k *KeyMap) *key.Binding { return &k.Refresh }},
+	{"broadcast", "broadcast", func(k *KeyMap) *key.Binding { return &k.Broadcast }},
+	{"faster", "sample faster", func(k *KeyMap) *key.Binding { return &k.Faster }},
+	{"slower", "sample slower", func(k *KeyMap) *key.Binding { return &k.Slower }},
+	{"reset", "reset", func(k *KeyMap) *key.Binding { return &k.Reset }},
+	{"search", "search", func(k *KeyMap) *key.Binding { return &k.Search }},
+	{"next_match", "next match", func(k *KeyMap) *key.Binding { return &k.NextMatch }},
+	{"prev_match", "previous match", func(k *KeyMap) *key.Binding { return &k.PrevMatch }},
+	{"jump_time", "jump to time", func(k *KeyMap) *key.Binding { return &k.JumpTime }},
+	{"level", "level", func(k *KeyMap) *key.Binding { return &k.Level }},
+	{"component", "component", func(k *KeyMap) *key.Binding { return &k.Component }},
+	{"older_file", "older file", func(k *KeyMap) *key.Binding { return &k.OlderFile }},
+	{"newer_file", "newer file", func(k *KeyMap) *key.Binding { return &k.NewerFile }},
+}
+
+// common are the keys every preset shares
+var common = map[string][]string{
+	"help":        {"?"},
+	"debug":       {"f12", "alt+d"},
+	"palette":     {"ctrl+p"},
+	"focus":       {"tab", "shift+tab"},
+	"page_up":     {"pgup"},
+	"page_down":   {"pgdown"},
+	"confirm":     {"enter"},
+	"cancel":      {"esc"},
+	"merge":       {"m"},
+	"mine":        {"o"},
+	"theirs":      {"t"},
+	"new_profile": {"n"},
+	"reset_flag":  {"d"},
+	"refresh":     {"r"},
+	"broadcast":   {"b"},
+	"faster":      {"+", "="},
+	"slower":      {"-"},
+	"reset":       {"r"},
+	"search":      {"/"},
+	"next_match":  {"n"},
+	"prev_match":  {"N"},
+	"jump_time":   {"t"},
+	"level":       {"v"},
+	"component":   {"c"},
+	"older_file":  {"["},
+	"newer_file":  {"]"},
+}
+
+var presets = map[string]map[string][]string{
+	"vim": {
+		"up":     {"up", "k"},
+		"down":   {"down", "j"},
+		"top":    {"g", "home"},
+		"bottom": {"G", "end"},
+		"select": {"enter", " "},
+		"back":   {"esc"},
+		"quit":   {"q", "ctrl+c"},
+		// Pages move as in less
+		"page_up":   {"pgup", "ctrl+b"},
+		"page_down": {"pgdown", "ctrl+f"},
+	},
+	"emacs": {
+		"up":     {"up", "ctrl+p"},
+		"down":   {"down", "ctrl+n"},
+		"top":    {"alt+<", "home"},
+		"bottom": {"alt+>", "end"},
+		"select": {"enter", " "},
+		"back":   {"esc", "ctrl+g"},
+		"quit":   {"q", "ctrl+c"},
+		"search": {"ctrl+s", "/"},
+		"cancel": {"esc", "ctrl+g"},
+		// ctrl+p moves up, as in the editor
+		"palette":   {"alt+x"},
+		"page_up":   {"pgup", "alt+v"},
+		"page_down": {"pgdown", "ctrl+v"},
+	},
+	"arrows": {
+		"up":     {"up"},
+		"down":   {"down"},
+		"top":    {"home"},
+		"bottom": {"end"},
+		"select": {"enter"},
+		"back":   {"esc", "left"},
+		"quit":   {"q", "ctrl+c"},
+	},
+}
+
+// Presets lists the names of the built-in presets in alphabetical order
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Preset returns the key map of a built-in preset
+func Preset(name string) (*KeyMap, error) {
+	p, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown key preset %q, expected one of %s", name, strings.Join(Presets(), ", "))
+	}
+	k := &KeyMap{}
+	for _, a := range actions {
+		keys, ok := p[a.name]
+		if !ok {
+			keys = common[a.name]
+		}
+		*a.binding(k) = binding(keys, a.desc)
+	}
+	return k, nil
+}
+
+// Default returns the key map of DefaultPreset
+func Default() *KeyMap {
+	k, _ := Preset(DefaultPreset)
+	return k
+}
+
+// file is the layout of keys.toml
+type file struct {
+	Preset   string              `toml:"preset"`
+	Bindings map[string][]string `toml:"bindings"`
+}
+
+// Load reads the key map from the keys.toml file at path. A missing file
+// gives the default preset. Problems with single bindings are reported but
+// the rest of the file still applies.
+func Load(path string) (*KeyMap, error) {
+	var f file
+	if _, err := toml.DecodeFile(path, &f); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Default(), nil
+		}
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	if f.Preset == "" {
+		f.Preset = DefaultPreset
+	}
+	k, err := Preset(f.Preset)
+	if err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+
+	var errs []string
+	names := make([]string, 0, len(f.Bindings))
+	for name := range f.Bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		a, ok := lookup(name)
+		if !ok {
+			errs = append(errs, fmt.Sprintf("unknown action %q", name))
+			continue
+		}
+		keys := f.Bindings[name]
+		for i, key := range keys {
+			if key == "space" {
+				keys[i] = " "
+			}
+		}
+		*a.binding(k) = binding(keys, a.desc)
+	}
+	if len(errs) > 0 {
+		return k, fmt.Errorf("%s: %s", path, strings.Join(errs, "; "))
+	}
+	return k, nil
+}
+
+// Bindings returns the keys of every action, keyed by the action names
+// keys.toml uses
+func (k *KeyMap) Bindings() map[string][]string {
+	b := make(map[string][]string, len(actions))
+	for _, a := range actions {
+		b[a.name] = a.binding(k).Keys()
+	}
+	return b
+}
+
+// Save writes bindings, as returned by Bindings, to path as a keys.toml
+// file. Actions it leaves out get the keys of DefaultPreset.
+func Save(path string, bindings map[string][]string) error {
+	f := file{Preset: DefaultPreset, Bindings: map[string][]string{}}
+	for name, keys := range bindings {
+		f.Bindings[name] = append([]string(nil), keys...)
+	}
+	for _, keys := range f.Bindings {
+		for i, key := range keys {
+			if key == " " {
+				keys[i] = "space"
+			}
+		}
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := toml.NewEncoder(out).Encode(f); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func lookup(name string) (action, bool) {
+	for _, a := range actions {
+		if a.name == name {
+			return a, true
+		}
+	}
+	return action{}, false
+}
+
+// binding builds a binding whose help shows the keys it is bound to, so
+// the help can never disagree with the bindings
+func binding(keys []string, desc string) key.Binding {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = label(k)
+	}
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(labels, "/"), desc))
+}
+
+func label(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case " ":
+		return "space"
+	}
+	return k
+}
+
+// Describe returns b with its help text changed to desc, for screens where
+// an action means something more specific
+func Describe(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
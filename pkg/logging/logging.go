@@ -0,0 +1,124 @@
+// Package logging routes Mainframe's messages through log/slog. The TUI
+// owns stdout, so records are written as JSON lines to rotating files in
+// the logs directory when logs are enabled and dropped otherwise.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Rotation limits for the log directory
+const (
+	MaxSize    = 10 << 20
+	MaxAge     = 24 * time.Hour
+	MaxBackups = 10
+	Retention  = 30 * 24 * time.Hour
+)
+
+var (
+	level   = new(slog.LevelVar)
+	current atomic.Pointer[slog.Handler]
+
+	mu   sync.Mutex
+	file *RotatingFile
+)
+
+func init() {
+	var h slog.Handler = discard{}
+	current.Store(&h)
+	slog.SetDefault(slog.New(&switchHandler{}))
+}
+
+// Apply starts or stops writing logs to dir and sets the level to debug
+// or info. It is cheap to call when nothing changed.
+func Apply(dir string, enabled, debug bool) error {
+	if debug {
+		level.Set(slog.LevelDebug)
+	} else {
+		level.Set(slog.LevelInfo)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if enabled == (file != nil) && (file == nil || file.Dir == dir) {
+		return nil
+	}
+
+	var h slog.Handler = discard{}
+	old := file
+	file = nil
+	if enabled {
+		file = &RotatingFile{
+			Dir:        dir,
+			MaxSize:    MaxSize,
+			MaxAge:     MaxAge,
+			MaxBackups: MaxBackups,
+			Retention:  Retention,
+		}
+		h = slog.NewJSONHandler(file, &slog.HandlerOptions{Level: level})
+	}
+	current.Store(&h)
+
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// Close flushes and closes the log file
+func Close() error {
+	return Apply("", false, level.Level() == slog.LevelDebug)
+}
+
+// For returns a logger whose records are tagged with component, such as
+// "ui" or "config"
+func For(component string) *slog.Logger {
+	return slog.Default().With("component", component)
+}
+
+// switchHandler forwards records to whichever handler Apply installed
+// last, so loggers created before logging was configured still work
+type switchHandler struct {
+	wrap []func(slog.Handler) slog.Handler
+}
+
+func (h *switchHandler) handler() slog.Handler {
+	inner := *current.Load()
+	for _, w := range h.wrap {
+		inner = w(inner)
+	}
+	return inner
+}
+
+func (h *switchHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return (*current.Load()).Enabled(ctx, l)
+}
+
+func (h *switchHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler().Handle(ctx, r)
+}
+
+func (h *switchHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithAttrs(attrs) })
+}
+
+func (h *switchHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithGroup(name) })
+}
+
+func (h *switchHandler) with(w func(slog.Handler) slog.Handler) slog.Handler {
+	wrap := append(append([]func(slog.Handler) slog.Handler{}, h.wrap...), w)
+	return &switchHandler{wrap: wrap}
+}
+
+// discard drops every record
+type discard struct{}
+
+func (discard) Enabled(context.Context, slog.Level) bool  { return false }
+func (discard) Handle(context.Context, slog.Record) error { return nil }
+func (d discard) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discard) WithGroup(string) slog.Handler           { return d }
@@ -0,0 +1,183 @@
+package logging
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is one record read back from a log file
+type Entry struct {
+	Time      time.Time
+	Level     string
+	Component string
+	Message   string
+	// Attrs holds the remaining fields as key=value pairs
+	Attrs string
+	// Raw is the line as written, used for searching
+	Raw string
+}
+
+// Files lists the log files in dir, the current file first followed by
+// rotated files from newest to oldest. Other *.log files, such as plain
+// text logs dropped there by hand, come last.
+func Files(dir string) ([]string, error) {
+	var files []string
+	current := filepath.Join(dir, CurrentFile)
+	if _, err := os.Stat(current); err == nil {
+		files = append(files, current)
+	}
+
+	backups, err := Backups(dir)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{current: true}
+	for _, b := range backups {
+		files = append(files, b.Path)
+		seen[b.Path] = true
+	}
+
+	others, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	sort.Strings(others)
+	for _, path := range others {
+		if !seen[path] {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
+
+// ReadFile reads every entry in path, decompressing .gz files. It returns
+// the number of bytes read so a live file can be followed with ReadFrom.
+func ReadFile(path string) ([]Entry, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer zr.Close()
+		r = zr
+	}
+	return readEntries(r)
+}
+
+// ReadFrom reads the complete lines appended to path after offset
+func ReadFrom(path string, offset int64) ([]Entry, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+
+	// The file was rotated or truncated, start over
+	if st, err := f.Stat(); err == nil && st.Size() < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+	entries, n, err := readEntries(f)
+	return entries, offset + n, err
+}
+
+// readEntries parses complete lines from r and reports how many bytes
+// they took up. A trailing line without a newline is left for later.
+func readEntries(r io.Reader) ([]Entry, int64, error) {
+	var entries []Entry
+	var n int64
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return entries, n, nil
+			}
+			return entries, n, err
+		}
+		n += int64(len(line))
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			entries = append(entries, ParseLine(line))
+		}
+	}
+}
+
+// ParseLine reads a JSON record written by this package, or failing that
+// a plain text line, picking out a leading timestamp and level if present
+func ParseLine(line string) Entry {
+	e := Entry{Raw: line}
+
+	var rec map[string]any
+	if json.Unmarshal([]byte(line), &rec) == nil {
+		if t, ok := rec["time"].(string); ok {
+			e.Time, _ = time.Parse(time.RFC3339Nano, t)
+		}
+		e.Level, _ = rec["level"].(string)
+		e.Component, _ = rec["component"].(string)
+		e.Message, _ = rec["msg"].(string)
+
+		var attrs []string
+		for _, k := range sortedKeys(rec) {
+			switch k {
+			case "time", "level", "component", "msg":
+				continue
+			}
+			attrs = append(attrs, fmt.Sprintf("%s=%v", k, rec[k]))
+		}
+		e.Attrs = strings.Join(attrs, " ")
+		return e
+	}
+
+	rest := line
+	if fields := strings.Fields(rest); len(fields) > 0 {
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006/01/02"} {
+			if t, err := time.Parse(layout, fields[0]); err == nil {
+				e.Time = t
+				rest = strings.TrimSpace(strings.TrimPrefix(rest, fields[0]))
+				// log.Printf writes the date and time as two fields
+				if layout == "2006/01/02" && len(fields) > 1 {
+					if tod, err := time.Parse("15:04:05", fields[1]); err == nil {
+						e.Time = t.Add(time.Duration(tod.Hour())*time.Hour + time.Duration(tod.Minute())*time.Minute + time.Duration(tod.Second())*time.Second)
+						rest = strings.TrimSpace(strings.TrimPrefix(rest, fields[1]))
+					}
+				}
+				break
+			}
+		}
+	}
+levels:
+	for _, level := range []string{"DEBUG", "INFO", "WARN", "ERROR"} {
+		for _, prefix := range []string{level + " ", "[" + level + "] ", "level=" + level + " "} {
+			if strings.HasPrefix(strings.ToUpper(rest), prefix) {
+				e.Level = level
+				rest = strings.TrimSpace(rest[len(prefix):])
+				break levels
+			}
+		}
+	}
+	e.Message = rest
+	return e
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,243 @@
+package logging
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CurrentFile is the name of the log file being written to. Rotated files
+// are renamed to mainframe-<time>.log, with _<n> added if a file rotated
+// in the same millisecond already has that name, and then gzipped.
+const CurrentFile = "mainframe.log"
+
+const rotatedLayout = "20060102-150405.000"
+
+// RotatingFile is an io.Writer that appends to CurrentFile in a directory,
+// starting a new file once the current one grows past MaxSize or gets
+// older than MaxAge. Old files are compressed and removed once there are
+// more than MaxBackups of them or they are older than Retention.
+type RotatingFile struct {
+	Dir        string
+	MaxSize    int64
+	MaxAge     time.Duration
+	MaxBackups int
+	Retention  time.Duration
+	// Clock tells the time, time.Now if nil
+	Clock func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	opened   time.Time
+	compress sync.WaitGroup
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+	if r.size > 0 && (r.size+int64(len(p)) > r.MaxSize || r.now().Sub(r.opened) > r.MaxAge) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file and waits for pending compression
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+	if r.file != nil {
+		err = r.file.Close()
+		r.file = nil
+	}
+	r.compress.Wait()
+	return err
+}
+
+func (r *RotatingFile) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock()
+}
+
+func (r *RotatingFile) open() error {
+	if err := os.MkdirAll(r.Dir, 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(r.Dir, CurrentFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.file = f
+	r.size = st.Size()
+	// A file left by an earlier run keeps aging from its first record
+	r.opened = r.now()
+	if t, ok := firstRecord(f.Name()); ok {
+		r.opened = t
+	}
+	return nil
+}
+
+// firstRecord reads the time of the first JSON record in path
+func firstRecord(path string) (time.Time, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil {
+		return time.Time{}, false
+	}
+	var rec struct {
+		Time time.Time `json:"time"`
+	}
+	if json.Unmarshal(line, &rec) != nil || rec.Time.IsZero() {
+		return time.Time{}, false
+	}
+	return rec.Time, true
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	rotated := r.rotatedName(r.now())
+	if err := os.Rename(filepath.Join(r.Dir, CurrentFile), rotated); err != nil {
+		return err
+	}
+	r.compress.Add(1)
+	go func() {
+		defer r.compress.Done()
+		if err := compressFile(rotated); err == nil {
+			r.prune()
+		}
+	}()
+	return r.open()
+}
+
+// rotatedName picks an unused name for a file rotated at t. The file
+// rotated before it may still be being compressed, so the .gz counts too.
+func (r *RotatingFile) rotatedName(t time.Time) string {
+	stamp := t.Format(rotatedLayout)
+	for n := 1; ; n++ {
+		name := filepath.Join(r.Dir, "mainframe-"+stamp+".log")
+		if n > 1 {
+			name = filepath.Join(r.Dir, fmt.Sprintf("mainframe-%s_%d.log", stamp, n))
+		}
+		_, err := os.Lstat(name)
+		_, gzErr := os.Lstat(name + ".gz")
+		if os.IsNotExist(err) && os.IsNotExist(gzErr) {
+			return name
+		}
+	}
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// prune removes rotated files beyond MaxBackups or older than Retention
+func (r *RotatingFile) prune() {
+	backups, err := Backups(r.Dir)
+	if err != nil {
+		return
+	}
+	for i, b := range backups {
+		if (r.MaxBackups > 0 && i >= r.MaxBackups) || (r.Retention > 0 && r.now().Sub(b.Rotated) > r.Retention) {
+			os.Remove(b.Path)
+		}
+	}
+}
+
+// Backup is a rotated log file
+type Backup struct {
+	Path    string
+	Rotated time.Time
+	// seq orders files rotated in the same millisecond
+	seq int
+}
+
+// Backups lists the rotated log files in dir, newest first
+func Backups(dir string) ([]Backup, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "mainframe-*.log*"))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, path := range paths {
+		stamp := strings.TrimPrefix(filepath.Base(path), "mainframe-")
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ".log")
+		stamp, n, _ := strings.Cut(stamp, "_")
+		seq, _ := strconv.Atoi(n)
+		t, err := time.ParseInLocation(rotatedLayout, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{path, t, seq})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].Rotated.Equal(backups[j].Rotated) {
+			return backups[i].Rotated.After(backups[j].Rotated)
+		}
+		return backups[i].seq > backups[j].seq
+	})
+	return backups, nil
+}
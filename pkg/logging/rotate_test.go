@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotateTwiceInOneSecond(t *testing.T) {
+	dir := t.TempDir()
+	// Every rotation happens in the same millisecond
+	now := time.Date(2026, time.January, 5, 9, 30, 0, 0, time.Local)
+	r := &RotatingFile{Dir: dir, MaxSize: 10, MaxAge: time.Hour, MaxBackups: 10, Retention: time.Hour, Clock: func() time.Time { return now }}
+
+	for i := 1; i <= 3; i++ {
+		line := fmt.Sprintf(`{"time":%q,"level":"INFO","msg":"record %d"}`+"\n", now.Format(time.RFC3339Nano), i)
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Files(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("got files %v, want the current file and two backups", files)
+	}
+	// Newest first, so the records come back in reverse order
+	for i, path := range files {
+		entries, _, err := ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := fmt.Sprintf("record %d", 3-i)
+		if len(entries) != 1 || entries[0].Message != want {
+			t.Errorf("%s: got %+v, want only %q", filepath.Base(path), entries, want)
+		}
+	}
+}
+
+func TestRotatedNameSkipsTakenNames(t *testing.T) {
+	dir := t.TempDir()
+	r := &RotatingFile{Dir: dir}
+	now := time.Date(2026, time.January, 5, 9, 30, 0, 0, time.Local)
+
+	first := r.rotatedName(now)
+	if err := os.WriteFile(first+".gz", nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	second := r.rotatedName(now)
+	if err := os.WriteFile(second, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	third := r.rotatedName(now)
+
+	want := []string{
+		"mainframe-20260105-093000.000.log",
+		"mainframe-20260105-093000.000_2.log",
+		"mainframe-20260105-093000.000_3.log",
+	}
+	for i, got := range []string{first, second, third} {
+		if filepath.Base(got) != want[i] {
+			t.Errorf("name %d = %s, want %s", i+1, filepath.Base(got), want[i])
+		}
+	}
+
+	backups, err := Backups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0].Path != second || backups[1].Path != first+".gz" {
+		t.Errorf("backups out of order: %+v", backups)
+	}
+}
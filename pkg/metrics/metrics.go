@@ -0,0 +1,202 @@
+// Package metrics measures how the app and the host it runs on are doing,
+// for the Performance Metrics panel.
+package metrics
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stat summarizes a series of durations
+type Stat struct {
+	Count int
+	Last  time.Duration
+	Max   time.Duration
+	Total time.Duration
+}
+
+// Mean is the average duration, or 0 if nothing was observed
+func (s Stat) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+func (s *Stat) observe(d time.Duration) {
+	s.Count++
+	s.Last = d
+	s.Total += d
+	if d > s.Max {
+		s.Max = d
+	}
+}
+
+// since returns what was observed after earlier was taken. Max cannot be
+// split, so it covers the whole series.
+func (s Stat) since(earlier Stat) Stat {
+	return Stat{Count: s.Count - earlier.Count, Last: s.Last, Max: s.Max, Total: s.Total - earlier.Total}
+}
+
+// Recorder collects render and update timings for one session
+type Recorder struct {
+	mu      sync.Mutex
+	frames  Stat
+	updates map[string]*Stat
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{updates: map[string]*Stat{}}
+}
+
+// Frame records how long a View call took
+func (r *Recorder) Frame(d time.Duration) {
+	r.mu.Lock()
+	r.frames.observe(d)
+	r.mu.Unlock()
+}
+
+// Update records how long an Update call for a message of kind took
+func (r *Recorder) Update(kind string, d time.Duration) {
+	r.mu.Lock()
+	s, ok := r.updates[kind]
+	if !ok {
+		s = &Stat{}
+		r.updates[kind] = s
+	}
+	s.observe(d)
+	r.mu.Unlock()
+}
+
+// Frames returns the render timings so far
+func (r *Recorder) Frames() Stat {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.frames
+}
+
+// UpdateStat is the update timing of one message type
+type UpdateStat struct {
+	Kind string
+	Stat
+}
+
+// Updates returns the update timings by message type, slowest on
+// average first
+func (r *Recorder) Updates() []UpdateStat {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make([]UpdateStat, 0, len(r.updates))
+	for kind, s := range r.updates {
+		stats = append(stats, UpdateStat{kind, *s})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Mean() != stats[j].Mean() {
+			return stats[i].Mean() > stats[j].Mean()
+		}
+		return stats[i].Kind < stats[j].Kind
+	})
+	return stats
+}
+
+// Reset forgets every timing
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.frames = Stat{}
+	r.updates = map[string]*Stat{}
+	r.mu.Unlock()
+}
+
+// Sample is a snapshot of the process and host
+type Sample struct {
+	Time       time.Time
+	RSS        uint64
+	HeapAlloc  uint64
+	HeapSys    uint64
+	Goroutines int
+	NumGC      uint32
+	// GCPause is the longest GC pause since the previous sample
+	GCPause time.Duration
+	// FrameTime is the mean View time since the previous sample
+	FrameTime time.Duration
+	// HostCPU is the fraction of host CPU time spent busy since the
+	// previous sample, or -1 if unknown
+	HostCPU      float64
+	HostMemUsed  uint64
+	HostMemTotal uint64
+}
+
+// History is the number of samples a Sampler keeps
+const History = 60
+
+// Sampler takes samples and keeps the most recent History of them
+type Sampler struct {
+	rec     *Recorder
+	samples []Sample
+	frames  Stat
+	numGC   uint32
+	cpu     cpuTimes
+}
+
+// NewSampler samples the process, attributing frame times from rec
+func NewSampler(rec *Recorder) *Sampler {
+	s := &Sampler{rec: rec, frames: rec.Frames(), cpu: readCPU()}
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	s.numGC = ms.NumGC
+	return s
+}
+
+// Sample takes a sample and adds it to the history
+func (s *Sampler) Sample() Sample {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	sample := Sample{
+		Time:       time.Now(),
+		RSS:        readRSS(ms.Sys),
+		HeapAlloc:  ms.HeapAlloc,
+		HeapSys:    ms.HeapSys,
+		Goroutines: runtime.NumGoroutine(),
+		NumGC:      ms.NumGC,
+		HostCPU:    -1,
+	}
+
+	// PauseNs is a ring buffer of the most recent 256 pauses, so after more
+	// GCs than that only the latest can be looked at
+	from := s.numGC
+	if ring := uint32(len(ms.PauseNs)); ms.NumGC-from > ring {
+		from = ms.NumGC - ring
+	}
+	for gc := from; gc < ms.NumGC; gc++ {
+		if p := time.Duration(ms.PauseNs[gc%uint32(len(ms.PauseNs))]); p > sample.GCPause {
+			sample.GCPause = p
+		}
+	}
+	s.numGC = ms.NumGC
+
+	frames := s.rec.Frames()
+	sample.FrameTime = frames.since(s.frames).Mean()
+	s.frames = frames
+
+	cpu := readCPU()
+	if total := cpu.total - s.cpu.total; cpu.ok && s.cpu.ok && total > 0 {
+		sample.HostCPU = 1 - float64(cpu.idle-s.cpu.idle)/float64(total)
+	}
+	s.cpu = cpu
+	sample.HostMemUsed, sample.HostMemTotal = readMemory()
+
+	s.samples = append(s.samples, sample)
+	if len(s.samples) > History {
+		s.samples = s.samples[len(s.samples)-History:]
+	}
+	return sample
+}
+
+// Samples returns the history, oldest first
+func (s *Sampler) Samples() []Sample {
+	return s.samples
+}
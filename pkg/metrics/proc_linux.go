@@ -0,0 +1,93 @@
+//go:build linux
+
+package metrics
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type cpuTimes struct {
+	idle, total uint64
+	ok          bool
+}
+
+// readCPU reads the aggregate CPU line of /proc/stat
+func readCPU() cpuTimes {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return cpuTimes{}
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	if !sc.Scan() {
+		return cpuTimes{}
+	}
+	fields := strings.Fields(sc.Text())
+	if len(fields) < 5 || fields[0] != "cpu" {
+		return cpuTimes{}
+	}
+
+	var t cpuTimes
+	for i, field := range fields[1:] {
+		v, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return cpuTimes{}
+		}
+		t.total += v
+		// idle and iowait
+		if i == 3 || i == 4 {
+			t.idle += v
+		}
+	}
+	t.ok = true
+	return t
+}
+
+// readMemory returns used and total host memory from /proc/meminfo
+func readMemory() (used, total uint64) {
+	values := readKB("/proc/meminfo", "MemTotal:", "MemAvailable:")
+	total, avail := values["MemTotal:"], values["MemAvailable:"]
+	if total == 0 || avail > total {
+		return 0, total
+	}
+	return total - avail, total
+}
+
+// readRSS returns the resident set size of the process, or fallback if
+// /proc is unavailable
+func readRSS(fallback uint64) uint64 {
+	if rss, ok := readKB("/proc/self/status", "VmRSS:")["VmRSS:"]; ok {
+		return rss
+	}
+	return fallback
+}
+
+// readKB reads the given "Key: <n> kB" lines of a /proc file in bytes
+func readKB(path string, keys ...string) map[string]uint64 {
+	values := map[string]uint64{}
+	f, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		for _, key := range keys {
+			if fields[0] == key {
+				if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+					values[key] = v * 1024
+				}
+			}
+		}
+	}
+	return values
+}
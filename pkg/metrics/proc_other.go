@@ -0,0 +1,16 @@
+//go:build !linux
+
+package metrics
+
+// Host figures come from /proc, which only Linux has
+
+type cpuTimes struct {
+	idle, total uint64
+	ok          bool
+}
+
+func readCPU() cpuTimes { return cpuTimes{} }
+
+func readMemory() (used, total uint64) { return 0, 0 }
+
+func readRSS(fallback uint64) uint64 { return fallback }
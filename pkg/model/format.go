@@ -0,0 +1,61 @@
+package model
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// Format is the on-disk layout of model weights
+type Format string
+
+const (
+	FormatGGUF        Format = "gguf"
+	FormatGGML        Format = "ggml"
+	FormatSafetensors Format = "safetensors"
+	FormatPyTorch     Format = "pytorch"
+	FormatUnknown     Format = "unknown"
+)
+
+// Supported reports whether the local backend can load weights in f
+func (f Format) Supported() bool {
+	return f == FormatGGUF
+}
+
+// DetectFormat identifies the weights at path from their first bytes
+func DetectFormat(path string) (Format, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return FormatUnknown, err
+	}
+	defer f.Close()
+
+	head := make([]byte, 16)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return FormatUnknown, err
+	}
+	return detect(head[:n]), nil
+}
+
+func detect(head []byte) Format {
+	switch {
+	case bytes.HasPrefix(head, []byte("GGUF")):
+		return FormatGGUF
+	// Pre-GGUF llama.cpp files start with a little-endian magic number
+	case bytes.HasPrefix(head, []byte("lmgg")),
+		bytes.HasPrefix(head, []byte("fmgg")),
+		bytes.HasPrefix(head, []byte("tjgg")),
+		bytes.HasPrefix(head, []byte("algg")):
+		return FormatGGML
+	// torch.save writes a zip archive, or a bare pickle in older versions
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")),
+		len(head) >= 2 && head[0] == 0x80 && head[1] <= 5:
+		return FormatPyTorch
+	// safetensors starts with the length of its JSON header
+	case len(head) >= 9 && head[8] == '{' && binary.LittleEndian.Uint64(head) < 100<<20:
+		return FormatSafetensors
+	}
+	return FormatUnknown
+}
@@ -0,0 +1,175 @@
+// Package model describes the local models Mainframe knows about and
+// manages their weights on disk.
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Model is an entry in the catalog of compatible local models
+type Model struct {
+	Name    string `json:"name"`
+	Family  string `json:"family"`
+	Params  string `json:"params"`
+	License string `json:"license"`
+	Page    string `json:"page"`
+	// URL is a direct download of the weights, empty when the model has
+	// to be fetched by hand from Page
+	URL  string `json:"url,omitempty"`
+	File string `json:"file,omitempty"`
+}
+
+var catalog = []Model{
+	{
+		Name:    "llama-2-7b",
+		Family:  "Llama 2",
+		Params:  "7B",
+		License: "Meta AI Research License",
+		Page:    "https://huggingface.co/meta-llama",
+		URL:     "https://huggingface.co/TheBloke/Llama-2-7B-GGUF/resolve/main/llama-2-7b.Q4_K_M.gguf",
+		File:    "llama-2-7b.Q4_K_M.gguf",
+	},
+	{
+		Name:    "gpt-j-6b",
+		Family:  "GPT-J",
+		Params:  "6B",
+		License: "Apache 2.0",
+		Page:    "https://huggingface.co/EleutherAI",
+	},
+	{
+		Name:    "bloom-7b1",
+		Family:  "BLOOM",
+		Params:  "7B",
+		License: "OpenRAIL-M",
+		Page:    "https://huggingface.co/bigscience",
+	},
+}
+
+// Catalog returns every known model
+func Catalog() []Model {
+	return catalog
+}
+
+// Lookup finds a catalog entry by name
+func Lookup(name string) (Model, error) {
+	for _, m := range catalog {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+	return Model{}, fmt.Errorf("unknown model %q", name)
+}
+
+// Path is where the weights of m live once downloaded into dir, or ""
+// if m cannot be downloaded automatically
+func (m Model) Path(dir string) string {
+	if m.File == "" {
+		return ""
+	}
+	return filepath.Join(dir, m.File)
+}
+
+// Downloaded reports whether the weights of m are present in dir
+func (m Model) Downloaded(dir string) bool {
+	path := m.Path(dir)
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Download fetches url into dest. The data is written next to dest first
+// and only moved into place once complete. progress, if not nil, is
+// called as data arrives with the bytes written so far and the total
+// size, which is -1 when the server does not say.
+func Download(ctx context.Context, url, dest string, progress func(done, total int64)) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download %s: %s", url, resp.Status)
+	}
+
+	tmp := dest + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := io.Writer(f)
+	if progress != nil {
+		w = &progressWriter{w: f, total: resp.ContentLength, report: progress}
+	}
+	_, err = io.Copy(w, resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+type progressWriter struct {
+	w      io.Writer
+	done   int64
+	total  int64
+	report func(done, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.done += int64(n)
+	p.report(p.done, p.total)
+	return n, err
+}
+
+// ErrNoPath is returned by Check when no model path is configured
+var ErrNoPath = errors.New("no model path configured")
+
+// Info describes model weights found on disk
+type Info struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Check makes sure the weights at path exist and can be read
+func Check(path string) (Info, error) {
+	if path == "" {
+		return Info{}, ErrNoPath
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return Info{Path: path}, err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return Info{Path: path}, err
+	}
+	if st.IsDir() {
+		return Info{Path: path}, fmt.Errorf("%s is a directory, expected a weights file", path)
+	}
+	if st.Size() == 0 {
+		return Info{Path: path, Size: 0}, fmt.Errorf("%s is empty", path)
+	}
+	return Info{Path: path, Size: st.Size()}, nil
+}
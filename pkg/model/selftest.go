@@ -0,0 +1,89 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Self-test steps, in the order they run
+const (
+	StepFile      = "file"
+	StepFormat    = "format"
+	StepServer    = "server"
+	StepInference = "inference"
+)
+
+// TestPrompt is the short prompt sent to check inference works
+const TestPrompt = "The capital of France is"
+
+// Step is the outcome of one self-test step
+type Step struct {
+	Name       string        `json:"name"`
+	OK         bool          `json:"ok"`
+	Detail     string        `json:"detail,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"-"`
+	DurationMS float64       `json:"duration_ms"`
+}
+
+// Report is the result of SelfTest
+type Report struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Format Format `json:"format"`
+	Server string `json:"server"`
+	Steps  []Step `json:"steps"`
+	OK     bool   `json:"ok"`
+	// Failed names the step that failed, if any
+	Failed string `json:"failed,omitempty"`
+}
+
+// SelfTest checks the weights at path, detects their format, makes sure
+// srv is up and serving them and runs a short inference, timing every
+// step. It stops at the first step that fails.
+func SelfTest(ctx context.Context, path string, srv *Server) Report {
+	r := Report{Path: path, Format: FormatUnknown, Server: srv.URL}
+
+	steps := []struct {
+		name string
+		run  func() (string, error)
+	}{
+		{StepFile, func() (string, error) {
+			info, err := Check(path)
+			r.Size = info.Size
+			return fmt.Sprintf("%d bytes", info.Size), err
+		}},
+		{StepFormat, func() (string, error) {
+			f, err := DetectFormat(path)
+			r.Format = f
+			if err == nil && !f.Supported() {
+				err = fmt.Errorf("%s weights are not supported by the local backend, convert them to GGUF", f)
+			}
+			return string(f), err
+		}},
+		{StepServer, func() (string, error) {
+			return srv.URL, srv.Serving(ctx, path)
+		}},
+		{StepInference, func() (string, error) {
+			_, n, err := srv.Complete(ctx, TestPrompt, 8)
+			return fmt.Sprintf("%d tokens", n), err
+		}},
+	}
+
+	for _, s := range steps {
+		start := time.Now()
+		detail, err := s.run()
+		step := Step{Name: s.name, OK: err == nil, Detail: detail, Duration: time.Since(start)}
+		step.DurationMS = float64(step.Duration.Microseconds()) / 1000
+		if err != nil {
+			step.Error = err.Error()
+			r.Steps = append(r.Steps, step)
+			r.Failed = s.name
+			return r
+		}
+		r.Steps = append(r.Steps, step)
+	}
+	r.OK = true
+	return r
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// llamaServer fakes the llama.cpp endpoints SelfTest uses. A field left
+// at its zero value gets the answer of a healthy server running model.
+type llamaServer struct {
+	model      string
+	health     int
+	completion int
+}
+
+func (l llamaServer) start(t *testing.T) *Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		if l.health != 0 {
+			w.WriteHeader(l.health)
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	})
+	mux.HandleFunc("/props", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"model_path": "/srv/models/" + l.model})
+	})
+	mux.HandleFunc("/completion", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Prompt string `json:"prompt"`
+			N      int    `json:"n_predict"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Prompt != TestPrompt {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if l.completion != 0 {
+			w.WriteHeader(l.completion)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"content": " Paris.", "tokens_predicted": req.N})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return NewServer(srv.URL)
+}
+
+// weights writes a weights file starting with head
+func weights(t *testing.T, name, head string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(head+strings.Repeat("\x00", 64)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSelfTestPasses(t *testing.T) {
+	path := weights(t, "llama.gguf", "GGUF")
+	srv := llamaServer{model: "llama.gguf"}.start(t)
+
+	r := SelfTest(context.Background(), path, srv)
+	if !r.OK || r.Failed != "" {
+		t.Fatalf("self-test failed: %+v", r)
+	}
+	if r.Format != FormatGGUF || r.Size != 68 || r.Server != srv.URL {
+		t.Errorf("report = %+v, want a 68 byte GGUF file on %s", r, srv.URL)
+	}
+	var names []string
+	for _, s := range r.Steps {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "file,format,server,inference" {
+		t.Errorf("steps %s, want every step in order", got)
+	}
+	if d := r.Steps[3].Detail; d != "8 tokens" {
+		t.Errorf("inference detail %q, want 8 tokens", d)
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out["ok"] != true || out["format"] != "gguf" || out["failed"] != nil {
+		t.Errorf("JSON report %s", data)
+	}
+	step := out["steps"].([]any)[0].(map[string]any)
+	if _, ok := step["duration_ms"]; !ok || step["name"] != StepFile {
+		t.Errorf("JSON step %v, want its name and duration_ms", step)
+	}
+}
+
+func TestSelfTestStopsAtTheFailingStep(t *testing.T) {
+	gguf := weights(t, "llama.gguf", "GGUF")
+	tests := []struct {
+		name   string
+		path   string
+		server llamaServer
+		failed string
+		error  string
+	}{
+		{"no path", "", llamaServer{model: "llama.gguf"}, StepFile, "no model path"},
+		{"missing file", filepath.Join(t.TempDir(), "missing.gguf"), llamaServer{model: "missing.gguf"}, StepFile, "no such file"},
+		{"safetensors", weights(t, "llama.safetensors", "\x10\x00\x00\x00\x00\x00\x00\x00{"), llamaServer{model: "llama.safetensors"}, StepFormat, "convert them to GGUF"},
+		{"server starting", gguf, llamaServer{model: "llama.gguf", health: http.StatusServiceUnavailable}, StepServer, "503"},
+		{"other weights", gguf, llamaServer{model: "mistral.gguf"}, StepServer, "running mistral.gguf, not llama.gguf"},
+		{"inference error", gguf, llamaServer{model: "llama.gguf", completion: http.StatusInternalServerError}, StepInference, "500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := SelfTest(context.Background(), tt.path, tt.server.start(t))
+			if r.OK || r.Failed != tt.failed {
+				t.Fatalf("failed = %q (ok %v), want %q", r.Failed, r.OK, tt.failed)
+			}
+			last := r.Steps[len(r.Steps)-1]
+			if last.Name != tt.failed || last.OK || !strings.Contains(last.Error, tt.error) {
+				t.Errorf("last step %+v, want %s failing with %q", last, tt.failed, tt.error)
+			}
+			for _, s := range r.Steps[:len(r.Steps)-1] {
+				if !s.OK {
+					t.Errorf("step %s failed before %s: %+v", s.Name, tt.failed, s)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		head string
+		want Format
+	}{
+		{"GGUF\x03\x00\x00\x00", FormatGGUF},
+		{"tjgg\x01\x00\x00\x00", FormatGGML},
+		{"PK\x03\x04", FormatPyTorch},
+		{"\x80\x02}q\x00", FormatPyTorch},
+		{"\x10\x00\x00\x00\x00\x00\x00\x00{\"a\"", FormatSafetensors},
+		{"hello world", FormatUnknown},
+	}
+	for _, tt := range tests {
+		got, err := DetectFormat(weights(t, "weights", tt.head))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("DetectFormat(%q) = %s, want %s", tt.head, got, tt.want)
+		}
+	}
+
+	if _, err := DetectFormat(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("DetectFormat of a missing file did not fail")
+	}
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// Server is a llama.cpp compatible HTTP server that runs local models
+type Server struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewServer talks to the server at url
+func NewServer(url string) *Server {
+	return &Server{URL: strings.TrimSuffix(url, "/"), Client: http.DefaultClient}
+}
+
+// Serving makes sure the server is up and running the weights at path. It
+// does not load them: llama.cpp picks its model at startup, so a server
+// running different weights is an error rather than something to change.
+func (s *Server) Serving(ctx context.Context, path string) error {
+	var health struct {
+		Status string `json:"status"`
+	}
+	if err := s.get(ctx, "/health", &health); err != nil {
+		return err
+	}
+	if health.Status != "" && health.Status != "ok" {
+		return fmt.Errorf("server is not ready: %s", health.Status)
+	}
+
+	var props struct {
+		ModelPath string `json:"model_path"`
+		Settings  struct {
+			Model string `json:"model"`
+		} `json:"default_generation_settings"`
+	}
+	if err := s.get(ctx, "/props", &props); err != nil {
+		return err
+	}
+	loaded := props.ModelPath
+	if loaded == "" {
+		loaded = props.Settings.Model
+	}
+	if loaded != "" && filepath.Base(loaded) != filepath.Base(path) {
+		return fmt.Errorf("server is running %s, not %s", filepath.Base(loaded), filepath.Base(path))
+	}
+	return nil
+}
+
+// Complete generates up to n tokens following prompt and returns the text
+// and the number of tokens generated
+func (s *Server) Complete(ctx context.Context, prompt string, n int) (string, int, error) {
+	body, _ := json.Marshal(map[string]any{"prompt": prompt, "n_predict": n})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL+"/completion", bytes.NewReader(body))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var out struct {
+		Content   string `json:"content"`
+		Predicted int    `json:"tokens_predicted"`
+	}
+	if err := s.do(req, &out); err != nil {
+		return "", 0, err
+	}
+	return out.Content, out.Predicted, nil
+}
+
+func (s *Server) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+path, nil)
+	if err != nil {
+		return err
+	}
+	return s.do(req, v)
+}
+
+func (s *Server) do(req *http.Request, v any) error {
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
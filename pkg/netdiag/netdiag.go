@@ -0,0 +1,270 @@
+// Package netdiag checks that the AI endpoint Mainframe talks to can be
+// reached, one network layer at a time, so a failure points at DNS, the
+// connection, TLS or the server itself.
+package netdiag
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Check names, in the order they run
+const (
+	CheckDNS  = "DNS"
+	CheckTCP  = "TCP"
+	CheckTLS  = "TLS"
+	CheckHTTP = "HTTP"
+)
+
+// Check is the outcome of one diagnostic
+type Check struct {
+	Name     string
+	OK       bool
+	Skipped  bool
+	Duration time.Duration
+	Detail   string
+	Error    string
+	// Hint suggests what to do about an error
+	Hint string
+}
+
+// Proxy describes the proxy settings from the environment and what they
+// mean for the endpoint. Passwords in the proxy URLs are redacted.
+type Proxy struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+	// Used is the proxy requests to the endpoint go through, or "" when
+	// they connect directly
+	Used string
+}
+
+// Report is the result of Run
+type Report struct {
+	Endpoint string
+	Proxy    Proxy
+	Checks   []Check
+}
+
+// OK reports whether every check that ran passed
+func (r Report) OK() bool {
+	for _, c := range r.Checks {
+		if !c.OK && !c.Skipped {
+			return false
+		}
+	}
+	return true
+}
+
+// Options lets callers swap the network pieces Run uses
+type Options struct {
+	Resolver *net.Resolver
+	// RootCAs verifies TLS certificates; the system pool if nil
+	RootCAs *x509.CertPool
+	// Proxy picks the proxy for a request; http.ProxyFromEnvironment if nil
+	Proxy func(*http.Request) (*url.URL, error)
+	// Timeout bounds each check; 10s if zero
+	Timeout time.Duration
+}
+
+// Run checks endpoint, stopping at the first layer that fails since the
+// layers above it cannot work either. When a proxy is in use the direct
+// checks may fail where requests through the proxy work, so the HTTP
+// check always runs.
+func Run(ctx context.Context, endpoint string, opts Options) Report {
+	if opts.Resolver == nil {
+		opts.Resolver = net.DefaultResolver
+	}
+	if opts.Proxy == nil {
+		opts.Proxy = http.ProxyFromEnvironment
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	r := Report{Endpoint: endpoint, Proxy: proxyEnv()}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		r.Checks = append(r.Checks, Check{Name: CheckDNS, Error: fmt.Sprintf("cannot parse endpoint %q", endpoint), Hint: "check api_base_url or model_server"})
+		return r
+	}
+	if req, err := http.NewRequest(http.MethodGet, endpoint, nil); err == nil {
+		if p, err := opts.Proxy(req); err == nil && p != nil {
+			r.Proxy.Used = p.Redacted()
+		}
+	}
+
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	var addrs []string
+	steps := []struct {
+		name string
+		run  func(ctx context.Context) (string, error)
+	}{
+		{CheckDNS, func(ctx context.Context) (string, error) {
+			if ip := net.ParseIP(host); ip != nil {
+				addrs = []string{host}
+				return "address given, no lookup needed", nil
+			}
+			var err error
+			addrs, err = opts.Resolver.LookupHost(ctx, host)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s → %v", host, addrs), nil
+		}},
+		{CheckTCP, func(ctx context.Context) (string, error) {
+			var d net.Dialer
+			conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(addrs[0], port))
+			if err != nil {
+				return "", err
+			}
+			conn.Close()
+			return "connected to " + net.JoinHostPort(addrs[0], port), nil
+		}},
+		{CheckTLS, func(ctx context.Context) (string, error) {
+			d := tls.Dialer{Config: &tls.Config{ServerName: host, RootCAs: opts.RootCAs}}
+			conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(addrs[0], port))
+			if err != nil {
+				return "", err
+			}
+			defer conn.Close()
+			state := conn.(*tls.Conn).ConnectionState()
+			cert := state.PeerCertificates[0]
+			return fmt.Sprintf("%s, certificate for %s from %s, expires %s",
+				tls.VersionName(state.Version), cert.Subject.CommonName, cert.Issuer.CommonName, cert.NotAfter.Format("2006-01-02")), nil
+		}},
+		{CheckHTTP, func(ctx context.Context) (string, error) {
+			dialer := &net.Dialer{Resolver: opts.Resolver}
+			client := &http.Client{Transport: &http.Transport{
+				Proxy:           opts.Proxy,
+				DialContext:     dialer.DialContext,
+				TLSClientConfig: &tls.Config{RootCAs: opts.RootCAs},
+			}}
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+			if err != nil {
+				return "", err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return "", err
+			}
+			resp.Body.Close()
+			// Any answer means the server is reachable; a 401 from an API
+			// that wants a key is expected here
+			if resp.StatusCode >= 500 {
+				return "", fmt.Errorf("server answered %s", resp.Status)
+			}
+			return "server answered " + resp.Status, nil
+		}},
+	}
+
+	failed := false
+	for _, s := range steps {
+		if failed && (s.name != CheckHTTP || r.Proxy.Used == "") {
+			r.Checks = append(r.Checks, Check{Name: s.name, Skipped: true, Detail: "skipped after an earlier failure"})
+			continue
+		}
+		if s.name == CheckTLS && u.Scheme != "https" {
+			r.Checks = append(r.Checks, Check{Name: s.name, Skipped: true, Detail: "skipped, the endpoint does not use https"})
+			continue
+		}
+
+		stepCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+		start := time.Now()
+		detail, err := s.run(stepCtx)
+		cancel()
+
+		c := Check{Name: s.name, OK: err == nil, Duration: time.Since(start), Detail: detail}
+		if err != nil {
+			c.Error = err.Error()
+			c.Hint = hint(s.name, err, r.Proxy)
+			failed = true
+		}
+		r.Checks = append(r.Checks, c)
+	}
+	return r
+}
+
+// hint explains common failures in terms of what the user can change
+func hint(check string, err error, proxy Proxy) string {
+	var unknownCA x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &unknownCA):
+		return "the certificate is signed by an unknown authority; if a company proxy inspects TLS, point SSL_CERT_FILE at its CA"
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		return "the certificate has expired or the system clock is wrong"
+	case errors.As(err, &invalid):
+		return "the certificate is not valid: " + invalid.Error()
+	case errors.As(err, &hostname):
+		return "the certificate does not match the host name; check the endpoint URL"
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		return "the host name does not exist; check the endpoint URL"
+	case errors.As(err, &dnsErr):
+		return "no DNS answer; check your network connection and resolver"
+	case errors.Is(err, context.DeadlineExceeded):
+		if proxy.Used == "" && check == CheckTCP {
+			return "timed out; a firewall may require going through a proxy (HTTPS_PROXY)"
+		}
+		return "timed out"
+	}
+	if check == CheckTCP {
+		if proxy.Used != "" {
+			return "direct connections fail but requests go through " + proxy.Used + "; see the HTTP check"
+		}
+		return "nothing is listening there; is the server running?"
+	}
+	return ""
+}
+
+func proxyEnv() Proxy {
+	get := func(name string) string {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+		return os.Getenv(strings.ToLower(name))
+	}
+	return Proxy{
+		HTTPProxy:  redact(get("HTTP_PROXY")),
+		HTTPSProxy: redact(get("HTTPS_PROXY")),
+		NoProxy:    get("NO_PROXY"),
+	}
+}
+
+// redact hides the password in a proxy URL. Like http.ProxyFromEnvironment
+// it accepts proxies given without a scheme.
+func redact(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		u, err = url.Parse("http://" + proxy)
+	}
+	if err != nil {
+		// Keep only what follows any credentials
+		if i := strings.LastIndex(proxy, "@"); i >= 0 {
+			return "xxxxx@" + proxy[i+1:]
+		}
+		return proxy
+	}
+	if u.User == nil {
+		return proxy
+	}
+	return u.Redacted()
+}
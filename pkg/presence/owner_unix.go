@@ -0,0 +1,39 @@
+//go:build unix
+
+package presence
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// checkOwner returns why path cannot be trusted as written by the user
+// with the given login name, or nil if it can. Only regular files count,
+// so a link planted in the spool directory is never followed.
+func checkOwner(path, name string) error {
+	if name == "" {
+		return errNoInstructor
+	}
+	u, err := user.Lookup(name)
+	if err != nil {
+		return err
+	}
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", path)
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("cannot tell who owns %s", path)
+	}
+	if owner := strconv.FormatUint(uint64(st.Uid), 10); owner != u.Uid {
+		return fmt.Errorf("%s belongs to uid %s, not to %s", path, owner, name)
+	}
+	return nil
+}
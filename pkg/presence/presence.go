@@ -0,0 +1,240 @@
+// Package presence lets Mainframe instances on a shared host announce what
+// their users are doing and receive messages from an instructor. Everything
+// goes through files in a spool directory: one heartbeat file per running
+// instance and a file per broadcast written by the instructor.
+package presence
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Interval is how often instances publish a heartbeat. Heartbeats older
+// than three intervals belong to instances that are gone.
+const Interval = 5 * time.Second
+
+// Broadcasts are written to broadcast.<id>.json. Heartbeat names never
+// contain a dot, and a fresh name for each one means nobody can block the
+// instructor by creating the file first.
+const broadcastPrefix = "broadcast."
+
+// errNoInstructor is why broadcasts are ignored when no instructor is set
+var errNoInstructor = errors.New("no instructor is set")
+
+// Heartbeat is the state an instance publishes about its user
+type Heartbeat struct {
+	ID        string    `json:"id"`
+	User      string    `json:"user"`
+	Host      string    `json:"host"`
+	PID       int       `json:"pid"`
+	Screen    string    `json:"screen"`
+	Activity  string    `json:"activity"`
+	Started   time.Time `json:"started"`
+	LastInput time.Time `json:"last_input"`
+	Updated   time.Time `json:"updated"`
+}
+
+// Idle reports how long the user has not pressed a key as of now
+func (h Heartbeat) Idle(now time.Time) time.Duration {
+	return now.Sub(h.LastInput).Truncate(time.Second)
+}
+
+// Broadcast is a message from the instructor to every instance
+type Broadcast struct {
+	ID      string    `json:"id"`
+	From    string    `json:"from"`
+	Message string    `json:"message"`
+	Sent    time.Time `json:"sent"`
+}
+
+// Publisher writes heartbeats for one instance
+type Publisher struct {
+	dir   string
+	id    string
+	start time.Time
+}
+
+// NewPublisher prepares to publish heartbeats for user into dir, creating
+// the directory world-writable with the sticky bit if it does not exist
+func NewPublisher(dir, user string) (*Publisher, error) {
+	if err := ensureDir(dir); err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	return &Publisher{
+		dir:   dir,
+		id:    fmt.Sprintf("%s-%s-%d-%s", safeName(user), safeName(host), os.Getpid(), randomID()),
+		start: time.Now(),
+	}, nil
+}
+
+// Publish writes hb, filling in the instance details
+func (p *Publisher) Publish(hb Heartbeat) error {
+	hb.ID = p.id
+	hb.Host, _ = os.Hostname()
+	hb.PID = os.Getpid()
+	hb.Started = p.start
+	if hb.Updated.IsZero() {
+		hb.Updated = time.Now()
+	}
+	return writeJSON(filepath.Join(p.dir, p.id+".json"), hb)
+}
+
+// Close removes the instance's heartbeat
+func (p *Publisher) Close() error {
+	return os.Remove(filepath.Join(p.dir, p.id+".json"))
+}
+
+// Read returns every live heartbeat in dir, ordered by user
+func Read(dir string, now time.Time) ([]Heartbeat, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var beats []Heartbeat
+	for _, path := range paths {
+		if strings.HasPrefix(filepath.Base(path), broadcastPrefix) {
+			continue
+		}
+		var hb Heartbeat
+		if err := readJSON(path, &hb); err != nil {
+			continue
+		}
+		if now.Sub(hb.Updated) > 3*Interval {
+			continue
+		}
+		beats = append(beats, hb)
+	}
+
+	sort.Slice(beats, func(i, j int) bool {
+		if beats[i].User != beats[j].User {
+			return beats[i].User < beats[j].User
+		}
+		return beats[i].Started.Before(beats[j].Started)
+	})
+	return beats, nil
+}
+
+// Send publishes a broadcast in a file of its own and removes the earlier
+// ones it is allowed to. Instances only show it if the sender is the
+// instructor they trust, see Latest.
+func Send(dir, from, message, instructor string) (Broadcast, error) {
+	if err := ensureDir(dir); err != nil {
+		return Broadcast{}, err
+	}
+	earlier, err := filepath.Glob(filepath.Join(dir, broadcastPrefix+"*.json"))
+	if err != nil {
+		return Broadcast{}, err
+	}
+	b := Broadcast{ID: randomID(), From: from, Message: message, Sent: time.Now()}
+	path := filepath.Join(dir, broadcastPrefix+b.ID+".json")
+	if err := writeJSON(path, b); err != nil {
+		return b, err
+	}
+	for _, p := range earlier {
+		os.Remove(p)
+	}
+	if err := checkOwner(path, instructor); err != nil {
+		return b, fmt.Errorf("instances will ignore the broadcast: %w", err)
+	}
+	return b, nil
+}
+
+// Latest returns the newest broadcast, if any. Broadcasts are only
+// trusted when the file belongs to the instructor, the login name set in
+// the instructor setting, so trainees who can write to the shared
+// directory cannot impersonate them. If there are broadcasts but none can
+// be trusted, the error says why.
+func Latest(dir, instructor string) (*Broadcast, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, broadcastPrefix+"*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var latest *Broadcast
+	var refused error
+	for _, path := range paths {
+		if err := checkOwner(path, instructor); err != nil {
+			refused = err
+			continue
+		}
+		var b Broadcast
+		if err := readJSON(path, &b); err != nil {
+			continue
+		}
+		if latest == nil || b.Sent.After(latest.Sent) {
+			latest = &b
+		}
+	}
+	if latest == nil && refused != nil {
+		return nil, fmt.Errorf("ignoring broadcasts: %w", refused)
+	}
+	return latest, nil
+}
+
+func ensureDir(dir string) error {
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return os.Chmod(dir, os.ModeSticky|0777)
+}
+
+// writeJSON replaces path atomically so readers never see half a file. The
+// temporary file has a random name, as anyone can create files in the
+// spool directory.
+func writeJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(0644)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func randomID() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func safeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '.' || r == ' ' {
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,89 @@
+//go:build unix
+
+package presence
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// instructor is the login name of whoever runs the tests, who owns every
+// file they write
+func instructor(t *testing.T) string {
+	t.Helper()
+	u, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Username
+}
+
+func TestSendReplacesTheEarlierBroadcast(t *testing.T) {
+	dir := t.TempDir()
+	name := instructor(t)
+	if _, err := Send(dir, name, "first", name); err != nil {
+		t.Fatal(err)
+	}
+	second, err := Send(dir, name, "second", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Latest(dir, name)
+	if err != nil || b == nil || b.ID != second.ID {
+		t.Fatalf("Latest = %+v, %v, want the second broadcast", b, err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, broadcastPrefix+"*"))
+	if len(files) != 1 {
+		t.Errorf("broadcast files %v, want only the newest", files)
+	}
+	beats, err := Read(dir, time.Now())
+	if err != nil || len(beats) != 0 {
+		t.Errorf("Read = %v, %v, want broadcasts left out of the heartbeats", beats, err)
+	}
+}
+
+func TestLatestIgnoresLinks(t *testing.T) {
+	dir := t.TempDir()
+	name := instructor(t)
+	sent, err := Send(dir, name, "hello", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A newer broadcast reached through a link, as a trainee could plant
+	target := filepath.Join(t.TempDir(), "fake.json")
+	if err := writeJSON(target, Broadcast{ID: "fake", Sent: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, filepath.Join(dir, broadcastPrefix+"fake.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Latest(dir, name)
+	if err != nil || b == nil || b.ID != sent.ID {
+		t.Errorf("Latest = %+v, %v, want %s and the link ignored", b, err, sent.ID)
+	}
+	if err := checkOwner(filepath.Join(dir, broadcastPrefix+"fake.json"), name); err == nil || !strings.Contains(err.Error(), "not a regular file") {
+		t.Errorf("checkOwner of a link = %v, want it refused", err)
+	}
+}
+
+func TestLatestSaysWhyBroadcastsAreIgnored(t *testing.T) {
+	dir := t.TempDir()
+	if b, err := Latest(dir, ""); b != nil || err != nil {
+		t.Fatalf("Latest with no broadcasts = %+v, %v", b, err)
+	}
+
+	name := instructor(t)
+	if _, err := Send(dir, name, "hello", ""); err == nil {
+		t.Error("Send with no instructor set did not warn that it will be ignored")
+	}
+	b, err := Latest(dir, "")
+	if b != nil || err == nil || !strings.Contains(err.Error(), "no instructor") {
+		t.Errorf("Latest = %+v, %v, want it refused as no instructor is set", b, err)
+	}
+}
@@ -0,0 +1,75 @@
+package styles
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SetAccessible switches linear output on or off. Linear output has no
+// borders, padding or centering, so screens read top to bottom as plain
+// text, and Plain spells out the icons.
+func (s *Styles) SetAccessible(on bool) {
+	if s.accessible == on {
+		return
+	}
+	s.accessible = on
+	s.build()
+}
+
+// Accessible reports whether the styles render linear output
+func (s *Styles) Accessible() bool {
+	return s.accessible
+}
+
+// linearize drops the decoration from every box and title
+func (s *Styles) linearize() {
+	for _, st := range []*lipgloss.Style{
+		&s.DocStyle, &s.SplitLeft, &s.SplitRight,
+		&s.AppTitle, &s.MainTitle, &s.SubTitle,
+		&s.MenuBox, &s.MenuOption, &s.HighlightedOption,
+		&s.ContentBox, &s.PageFooter, &s.MenuFooter, &s.InputBox, &s.DialogBox,
+		&s.SectionTitle, &s.Description, &s.StatusIndicator,
+	} {
+		*st = st.Copy().
+			UnsetBorderStyle().
+			UnsetBorderTop().
+			UnsetBorderRight().
+			UnsetBorderBottom().
+			UnsetBorderLeft().
+			UnsetPadding().
+			UnsetMargins().
+			UnsetAlign()
+	}
+	// Keep a blank line between sections
+	s.SectionTitle = s.SectionTitle.MarginTop(1)
+}
+
+// icons are the symbols the UI draws and what linear output says instead
+var icons = strings.NewReplacer(
+	"◉", "(on)",
+	"○", "( )",
+	"►", ">",
+	"▸", ">",
+	"✓", "OK",
+	"✗", "FAILED",
+	"⊘", "(none)",
+	"•", "-",
+	"…", "...",
+	"←", "<-",
+	"→", "->",
+	"↑", "up",
+	"↓", "down",
+	"≥", ">=",
+	"🤖", "*",
+	"█", "#",
+	"░", ".",
+)
+
+// Plain replaces the icons in view with ASCII when output is linear
+func (s *Styles) Plain(view string) string {
+	if !s.accessible {
+		return view
+	}
+	return icons.Replace(view)
+}
@@ -0,0 +1,111 @@
+package styles
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Color modes accepted by SetColorMode
+const (
+	ColorAuto      = "auto"
+	ColorTrueColor = "truecolor"
+	Color256       = "256"
+	Color16        = "16"
+	ColorNone      = "none"
+)
+
+// Background modes accepted by SetBackgroundMode
+const (
+	BackgroundAuto  = "auto"
+	BackgroundDark  = "dark"
+	BackgroundLight = "light"
+)
+
+// SetColorMode picks how many colors are rendered. Auto detects what the
+// terminal supports and honors NO_COLOR and CLICOLOR; any other mode
+// overrides detection.
+func (s *Styles) SetColorMode(mode string) error {
+	var profile termenv.Profile
+	switch mode {
+	case ColorAuto, "":
+		profile = s.renderer.Output().EnvColorProfile()
+	case ColorTrueColor:
+		profile = termenv.TrueColor
+	case Color256:
+		profile = termenv.ANSI256
+	case Color16:
+		profile = termenv.ANSI
+	case ColorNone:
+		profile = termenv.Ascii
+	default:
+		return fmt.Errorf("unknown color mode %q", mode)
+	}
+	s.renderer.SetColorProfile(profile)
+	return nil
+}
+
+// ColorMode returns the mode of the colors rendered right now, never auto
+func (s *Styles) ColorMode() string {
+	switch s.renderer.ColorProfile() {
+	case termenv.TrueColor:
+		return ColorTrueColor
+	case termenv.ANSI256:
+		return Color256
+	case termenv.ANSI:
+		return Color16
+	}
+	return ColorNone
+}
+
+// BackgroundMode returns the palette in use, dark or light, never auto
+func (s *Styles) BackgroundMode() string {
+	if s.renderer.HasDarkBackground() {
+		return BackgroundDark
+	}
+	return BackgroundLight
+}
+
+// SetBackgroundMode picks between the dark and light palettes. Auto asks
+// the terminal, so it must be called before the TUI takes over input.
+func (s *Styles) SetBackgroundMode(mode string) error {
+	switch mode {
+	case BackgroundAuto, "":
+		s.renderer.SetHasDarkBackground(s.renderer.Output().HasDarkBackground())
+	case BackgroundDark:
+		s.renderer.SetHasDarkBackground(true)
+	case BackgroundLight:
+		s.renderer.SetHasDarkBackground(false)
+	default:
+		return fmt.Errorf("unknown background mode %q", mode)
+	}
+	return nil
+}
+
+// adaptive builds a color that follows the terminal background and
+// degrades to the nearest 256 and 16 color values. Backgrounds are dropped
+// entirely on 16 color terminals, where a forced fill rarely looks right.
+func adaptive(dark, light string, background bool) lipgloss.TerminalColor {
+	return lipgloss.CompleteAdaptiveColor{
+		Dark:  complete(dark, background),
+		Light: complete(light, background),
+	}
+}
+
+func complete(hex string, background bool) lipgloss.CompleteColor {
+	if hex == "" {
+		return lipgloss.CompleteColor{}
+	}
+	rgb := termenv.RGBColor(hex)
+	c := lipgloss.CompleteColor{TrueColor: hex}
+	// Convert returns nil for malformed hex values
+	if c256, ok := termenv.ANSI256.Convert(rgb).(termenv.ANSI256Color); ok {
+		c.ANSI256 = strconv.Itoa(int(c256))
+	}
+	if c16, ok := termenv.ANSI.Convert(rgb).(termenv.ANSIColor); ok && !background {
+		c.ANSI = strconv.Itoa(int(c16))
+	}
+	return c
+}
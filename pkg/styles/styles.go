@@ -1,165 +1,324 @@
 package styles
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
-var (
+// Styles holds every style used by the UI for one terminal session. Each
+// session builds its own from its renderer, so sessions with different
+// sizes, themes or color support can render at the same time.
+type Styles struct {
+	renderer *lipgloss.Renderer
+	theme    Theme
+	// custom holds the themes loaded with LoadThemes
+	custom map[string]Theme
+	width  int
+	height int
+	// accessible renders linear output, see SetAccessible
+	accessible bool
+
+	// Layout styles
+	DocStyle   lipgloss.Style
+	SplitLeft  lipgloss.Style
+	SplitRight lipgloss.Style
+
+	// Title styles
+	AppTitle  lipgloss.Style
+	MainTitle lipgloss.Style
+	SubTitle  lipgloss.Style
+
+	// Menu styles
+	MenuBox           lipgloss.Style
+	MenuOption        lipgloss.Style
+	HighlightedOption lipgloss.Style
+
+	// Content styles
+	ContentBox lipgloss.Style
+
+	// Footer styles
+	PageFooter lipgloss.Style
+	// MenuFooter is PageFooter sized to fit inside MenuBox
+	MenuFooter lipgloss.Style
+	HelpKey    lipgloss.Style
+	HelpDesc   lipgloss.Style
+
+	// Text styles
+	ErrorText   lipgloss.Style
+	SuccessText lipgloss.Style
+	WarningText lipgloss.Style
+
+	// Input styles
+	InputBox lipgloss.Style
+
+	// Dialog styles
+	DialogBox lipgloss.Style
+
+	// Section styles
+	SectionTitle lipgloss.Style
+
+	// Description styles
+	Description lipgloss.Style
+
+	// Status styles
+	StatusIndicator lipgloss.Style
+}
+
+// New builds styles for the session rendered by r, using the default theme
+func New(r *lipgloss.Renderer) *Styles {
+	s := &Styles{renderer: r, theme: themes[DefaultTheme]}
+	s.build()
+	return s
+}
+
+// Renderer returns the renderer the styles were built for
+func (s *Styles) Renderer() *lipgloss.Renderer {
+	return s.renderer
+}
+
+// Theme returns the theme the styles are built from
+func (s *Styles) Theme() Theme {
+	return s.theme
+}
+
+// SetTheme rebuilds every style from the named theme
+func (s *Styles) SetTheme(name string) error {
+	if name == "" {
+		name = DefaultTheme
+	}
+	t, ok := s.findTheme(name)
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+	s.theme = t
+	s.build()
+	return nil
+}
+
+// build recreates every style from the colors of the theme
+func (s *Styles) build() {
+	t := s.theme
+
 	// Colors
-	primaryColor   = lipgloss.Color("#7D56F4")
-	secondaryColor = lipgloss.Color("#5B5B5B")
-	bgColor        = lipgloss.Color("#1A1B26")
-	textColor      = lipgloss.Color("#FFFFFF")
-	errorColor     = lipgloss.Color("#FF0000")
-	successColor   = lipgloss.Color("#00FF00")
-	warningColor   = lipgloss.Color("#FFA500")
-	accentColor    = lipgloss.Color("#FF79C6")
+	primaryColor := adaptive(t.Primary, t.Light.Primary, false)
+	bgColor := adaptive(t.Background, t.Light.Background, true)
+	textColor := adaptive(t.Text, t.Light.Text, false)
+	mutedColor := adaptive(t.Muted, t.Light.Muted, false)
+	errorColor := adaptive(t.Error, t.Light.Error, false)
+	successColor := adaptive(t.Success, t.Light.Success, false)
+	warningColor := adaptive(t.Warning, t.Light.Warning, false)
+	accentColor := adaptive(t.Accent, t.Light.Accent, false)
 
 	// Layout styles
-	DocStyle = lipgloss.NewStyle().
-			Padding(1, 2).
-			Background(bgColor)
-
-	SplitLeft = lipgloss.NewStyle().
-			Width(30).
-			Height(30).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(1).
-			MarginRight(2)
-
-	SplitRight = lipgloss.NewStyle().
-			Width(90).
-			Height(30).
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(1)
+	s.DocStyle = s.renderer.NewStyle().
+		Padding(1, 2).
+		Background(bgColor)
+
+	s.SplitLeft = s.renderer.NewStyle().
+		Width(30).
+		Height(30).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1).
+		MarginRight(2)
+
+	s.SplitRight = s.renderer.NewStyle().
+		Width(90).
+		Height(30).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1)
 
 	// Title styles
-	AppTitle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			Background(bgColor).
-			Padding(1, 4).
-			MarginBottom(2).
-			BorderStyle(lipgloss.DoubleBorder()).
-			BorderForeground(primaryColor).
-			Align(lipgloss.Center)
-
-	MainTitle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			Background(bgColor).
-			Padding(1, 4).
-			MarginTop(1).
-			MarginBottom(2).
-			BorderStyle(lipgloss.DoubleBorder()).
-			BorderForeground(primaryColor).
-			Align(lipgloss.Center)
-
-	SubTitle = lipgloss.NewStyle().
-			Foreground(textColor).
-			Background(bgColor).
-			Padding(0, 2).
-			MarginTop(1).
-			MarginBottom(2).
-			Align(lipgloss.Center)
+	s.AppTitle = s.renderer.NewStyle().
+		Bold(true).
+		Foreground(primaryColor).
+		Background(bgColor).
+		Padding(1, 4).
+		MarginBottom(2).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(primaryColor).
+		Align(lipgloss.Center)
+
+	s.MainTitle = s.renderer.NewStyle().
+		Bold(true).
+		Foreground(primaryColor).
+		Background(bgColor).
+		Padding(1, 4).
+		MarginTop(1).
+		MarginBottom(2).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(primaryColor).
+		Align(lipgloss.Center)
+
+	s.SubTitle = s.renderer.NewStyle().
+		Foreground(textColor).
+		Background(bgColor).
+		Padding(0, 2).
+		MarginTop(1).
+		MarginBottom(2).
+		Align(lipgloss.Center)
 
 	// Menu styles
-	MenuBox = lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
+	s.MenuBox = s.renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
 		BorderForeground(primaryColor).
 		Padding(1).
 		MarginRight(2)
 
-	MenuOption = lipgloss.NewStyle().
-			Foreground(textColor).
-			Padding(0, 2).
-			MarginTop(0).
-			MarginBottom(0)
+	s.MenuOption = s.renderer.NewStyle().
+		Foreground(textColor).
+		Padding(0, 2).
+		MarginTop(0).
+		MarginBottom(0)
 
-	HighlightedOption = lipgloss.NewStyle().
-				Foreground(primaryColor).
-				Bold(true).
-				Padding(0, 2).
-				MarginTop(0).
-				MarginBottom(0)
+	s.HighlightedOption = s.renderer.NewStyle().
+		Foreground(primaryColor).
+		Bold(true).
+		Padding(0, 2).
+		MarginTop(0).
+		MarginBottom(0)
 
 	// Content styles
-	ContentBox = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(1).
-			MarginLeft(2)
+	s.ContentBox = s.renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1).
+		MarginLeft(2)
 
 	// Footer styles
-	PageFooter = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#999999")).
-			Padding(1, 2).
-			MarginTop(1).
-			Align(lipgloss.Center)
+	s.PageFooter = s.renderer.NewStyle().
+		Foreground(mutedColor).
+		Padding(1, 2).
+		MarginTop(1).
+		Align(lipgloss.Center)
+	s.MenuFooter = s.PageFooter.Copy()
+
+	s.HelpKey = s.renderer.NewStyle().
+		Foreground(primaryColor).
+		Bold(true)
+
+	s.HelpDesc = s.renderer.NewStyle().
+		Foreground(mutedColor)
 
 	// Text styles
-	ErrorText = lipgloss.NewStyle().
-			Foreground(errorColor).
-			Bold(true)
+	s.ErrorText = s.renderer.NewStyle().
+		Foreground(errorColor).
+		Bold(true)
 
-	SuccessText = lipgloss.NewStyle().
-			Foreground(successColor).
-			Bold(true)
+	s.SuccessText = s.renderer.NewStyle().
+		Foreground(successColor).
+		Bold(true)
 
-	WarningText = lipgloss.NewStyle().
-			Foreground(warningColor).
-			Bold(true)
+	s.WarningText = s.renderer.NewStyle().
+		Foreground(warningColor).
+		Bold(true)
 
 	// Input styles
-	InputBox = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(1, 2)
+	s.InputBox = s.renderer.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2)
 
 	// Dialog styles
-	DialogBox = lipgloss.NewStyle().
-			BorderStyle(lipgloss.DoubleBorder()).
-			BorderForeground(primaryColor).
-			Padding(1, 2).
-			MarginTop(1).
-			MarginBottom(1)
+	s.DialogBox = s.renderer.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		MarginTop(1).
+		MarginBottom(1)
 
 	// Section styles
-	SectionTitle = lipgloss.NewStyle().
-			Foreground(primaryColor).
-			Bold(true).
-			MarginBottom(1).
-			MarginTop(1)
+	s.SectionTitle = s.renderer.NewStyle().
+		Foreground(primaryColor).
+		Bold(true).
+		MarginBottom(1).
+		MarginTop(1)
 
 	// Description styles
-	Description = lipgloss.NewStyle().
-			Foreground(textColor).
-			MarginTop(1).
-			MarginBottom(1)
+	s.Description = s.renderer.NewStyle().
+		Foreground(textColor).
+		MarginTop(1).
+		MarginBottom(1)
 
 	// Status styles
-	StatusIndicator = lipgloss.NewStyle().
-			Foreground(accentColor).
-			Bold(true).
-			Padding(0, 1)
-)
+	s.StatusIndicator = s.renderer.NewStyle().
+		Foreground(accentColor).
+		Bold(true).
+		Padding(0, 1)
+
+	if s.accessible {
+		s.linearize()
+	}
+	if s.width > 0 {
+		s.layout()
+	}
+}
+
+// compactHeight is the terminal height below which the blank lines around
+// titles, menus and footers are dropped
+const compactHeight = 30
+
+// minMenuWidth keeps the menu pane wide enough that its options wrap
+// between words
+const minMenuWidth = 32
+
+// Resize rebuilds the styles for the given terminal dimensions
+func (s *Styles) Resize(width, height int) {
+	s.width, s.height = width, height
+	s.build()
+}
+
+// layout sizes the panes, boxes and titles to the terminal. Widths in
+// lipgloss include padding but not borders or margins, and heights include
+// padding but not borders.
+func (s *Styles) layout() {
+	if s.height < compactHeight && !s.accessible {
+		s.SplitLeft = s.SplitLeft.Padding(0, 1)
+		s.SplitRight = s.SplitRight.Padding(0, 1)
+		s.AppTitle = s.AppTitle.Padding(0, 4).MarginBottom(0)
+		s.MainTitle = s.MainTitle.Padding(0, 4).MarginTop(0).MarginBottom(1)
+		s.SubTitle = s.SubTitle.MarginTop(0).MarginBottom(0)
+		s.MenuBox = s.MenuBox.Padding(0, 1)
+		s.PageFooter = s.PageFooter.Padding(0, 2).MarginTop(0)
+		s.DialogBox = s.DialogBox.Padding(0, 2).MarginTop(0).MarginBottom(0)
+		s.SectionTitle = s.SectionTitle.MarginTop(0).MarginBottom(0)
+		s.Description = s.Description.MarginBottom(0)
+	}
+
+	// The menu pane gets a quarter of the width and the content pane what
+	// the document's padding and the panes' frames leave of the rest
+	docWidth, docHeight := s.DocStyle.GetFrameSize()
+	leftWidth := min(max(s.width/4, minMenuWidth), s.width/2)
+	leftFrame := s.SplitLeft.GetHorizontalBorderSize() + s.SplitLeft.GetHorizontalMargins()
+	rightFrame := s.SplitRight.GetHorizontalBorderSize() + s.SplitRight.GetHorizontalMargins()
+	rightWidth := s.width - docWidth - leftWidth - leftFrame - rightFrame
+	viewHeight := s.height - docHeight - s.SplitLeft.GetVerticalBorderSize()
+	if s.accessible {
+		// Linear output stacks the panes, so each has the whole width
+		leftWidth, rightWidth = s.width, s.width
+	}
+	s.SplitLeft = s.SplitLeft.Width(leftWidth).Height(viewHeight)
+	s.SplitRight = s.SplitRight.Width(rightWidth).Height(viewHeight)
+
+	// Boxes inside the panes lose the pane's padding and their own border
+	// and margin
+	menuWidth := leftWidth - s.SplitLeft.GetHorizontalPadding() - s.MenuBox.GetHorizontalBorderSize() - s.MenuBox.GetHorizontalMargins()
+	s.MenuBox = s.MenuBox.Width(menuWidth)
+	s.MenuFooter = s.PageFooter.Copy().Width(menuWidth - s.MenuBox.GetHorizontalPadding())
+	contentWidth := rightWidth - s.SplitRight.GetHorizontalPadding() - s.ContentBox.GetHorizontalBorderSize() - s.ContentBox.GetHorizontalMargins()
+	s.ContentBox = s.ContentBox.Width(contentWidth)
+	s.MainTitle = s.MainTitle.Width(contentWidth - s.ContentBox.GetHorizontalPadding() - s.MainTitle.GetHorizontalBorderSize())
+
+	// Dialogs fill the width of the document, so their titles and footers
+	// are sized to fit inside one
+	s.AppTitle = s.AppTitle.Width(s.DialogWidth() - s.AppTitle.GetHorizontalBorderSize())
+	s.SubTitle = s.SubTitle.Width(s.DialogWidth())
+	s.PageFooter = s.PageFooter.Width(s.DialogWidth())
+}
 
-// UpdateSplitSizes updates the split view sizes based on terminal dimensions
-func UpdateSplitSizes(width, height int) {
-	leftWidth := width / 4
-	rightWidth := width - leftWidth - 4 // Account for margins and borders
-	viewHeight := height - 4            // Account for margins and borders
-
-	SplitLeft = SplitLeft.Width(leftWidth).Height(viewHeight)
-	SplitRight = SplitRight.Width(rightWidth).Height(viewHeight)
-
-	// Update dependent styles
-	MenuBox = MenuBox.Width(leftWidth - 2)        // Account for padding
-	ContentBox = ContentBox.Width(rightWidth - 2) // Account for padding
-	AppTitle = AppTitle.Width(width - 4)          // Account for margins
-	MainTitle = MainTitle.Width(rightWidth - 4)   // Account for margins
-	SubTitle = SubTitle.Width(rightWidth - 4)     // Account for margins
-	PageFooter = PageFooter.Width(width - 4)      // Account for margins
+// DialogWidth is the width inside a dialog box as wide as the document
+func (s *Styles) DialogWidth() int {
+	return max(s.width-s.DocStyle.GetHorizontalFrameSize()-s.DialogBox.GetHorizontalFrameSize(), 0)
 }
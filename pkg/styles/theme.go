@@ -0,0 +1,245 @@
+package styles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DefaultTheme is used when no theme has been chosen
+const DefaultTheme = "default"
+
+// Palette is a set of colors. Colors are hex strings such as #7D56F4; an
+// empty Background leaves the terminal's own background alone.
+type Palette struct {
+	Primary    string `toml:"primary"`
+	Secondary  string `toml:"secondary"`
+	Background string `toml:"background"`
+	Text       string `toml:"text"`
+	Muted      string `toml:"muted"`
+	Error      string `toml:"error"`
+	Success    string `toml:"success"`
+	Warning    string `toml:"warning"`
+	Accent     string `toml:"accent"`
+}
+
+// Theme is a named pair of palettes, one for dark terminals and one for
+// light ones. In theme files the dark colors sit at the top level and the
+// light ones in a [light] table.
+type Theme struct {
+	Name string `toml:"name"`
+	Palette
+	Light Palette `toml:"light"`
+}
+
+// defaultLight is used for any light color a theme leaves out
+var defaultLight = Palette{
+	Primary:    "#5A3FC0",
+	Secondary:  "#8A8A8A",
+	Background: "",
+	Text:       "#1A1B26",
+	Muted:      "#6B6B6B",
+	Error:      "#C00000",
+	Success:    "#007A00",
+	Warning:    "#A35F00",
+	Accent:     "#B0306F",
+}
+
+var themes = map[string]Theme{
+	"default": {
+		Name: "default",
+		Palette: Palette{
+			Primary:    "#7D56F4",
+			Secondary:  "#5B5B5B",
+			Background: "#1A1B26",
+			Text:       "#FFFFFF",
+			Muted:      "#999999",
+			Error:      "#FF0000",
+			Success:    "#00FF00",
+			Warning:    "#FFA500",
+			Accent:     "#FF79C6",
+		},
+		Light: defaultLight,
+	},
+	"solarized": {
+		Name: "solarized",
+		Palette: Palette{
+			Primary:    "#268BD2",
+			Secondary:  "#586E75",
+			Background: "#002B36",
+			Text:       "#EEE8D5",
+			Muted:      "#839496",
+			Error:      "#DC322F",
+			Success:    "#859900",
+			Warning:    "#B58900",
+			Accent:     "#D33682",
+		},
+		Light: Palette{
+			Primary:    "#268BD2",
+			Secondary:  "#93A1A1",
+			Background: "#FDF6E3",
+			Text:       "#073642",
+			Muted:      "#657B83",
+			Error:      "#DC322F",
+			Success:    "#859900",
+			Warning:    "#B58900",
+			Accent:     "#D33682",
+		},
+	},
+	"high-contrast": {
+		Name: "high-contrast",
+		Palette: Palette{
+			Primary:    "#FFFF00",
+			Secondary:  "#FFFFFF",
+			Background: "#000000",
+			Text:       "#FFFFFF",
+			Muted:      "#FFFFFF",
+			Error:      "#FF5555",
+			Success:    "#55FF55",
+			Warning:    "#FFFF55",
+			Accent:     "#55FFFF",
+		},
+		Light: Palette{
+			Primary:    "#0000C0",
+			Secondary:  "#000000",
+			Background: "#FFFFFF",
+			Text:       "#000000",
+			Muted:      "#000000",
+			Error:      "#B00000",
+			Success:    "#006000",
+			Warning:    "#804000",
+			Accent:     "#800080",
+		},
+	},
+	"monochrome": {
+		Name: "monochrome",
+		Palette: Palette{
+			Primary:    "#FFFFFF",
+			Secondary:  "#808080",
+			Background: "#000000",
+			Text:       "#D0D0D0",
+			Muted:      "#808080",
+			Error:      "#FFFFFF",
+			Success:    "#FFFFFF",
+			Warning:    "#D0D0D0",
+			Accent:     "#FFFFFF",
+		},
+		Light: Palette{
+			Primary:    "#000000",
+			Secondary:  "#808080",
+			Background: "#FFFFFF",
+			Text:       "#303030",
+			Muted:      "#808080",
+			Error:      "#000000",
+			Success:    "#000000",
+			Warning:    "#303030",
+			Accent:     "#000000",
+		},
+	},
+}
+
+// Themes lists the names of the built-in themes and the custom ones
+// loaded for this session in alphabetical order
+func (s *Styles) Themes() []string {
+	names := make([]string, 0, len(themes)+len(s.custom))
+	for name := range themes {
+		names = append(names, name)
+	}
+	for name := range s.custom {
+		if _, ok := themes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CustomThemes returns the themes loaded with LoadThemes, ordered by name
+func (s *Styles) CustomThemes() []Theme {
+	list := make([]Theme, 0, len(s.custom))
+	for _, t := range s.custom {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// SaveTheme writes t to path as a theme file LoadThemes reads back
+func SaveTheme(path string, t Theme) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := toml.NewEncoder(out).Encode(t); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// findTheme looks the name up among the session's custom themes, then the
+// built-in ones
+func (s *Styles) findTheme(name string) (Theme, bool) {
+	if t, ok := s.custom[name]; ok {
+		return t, true
+	}
+	t, ok := themes[name]
+	return t, ok
+}
+
+// LoadThemes adds every *.toml file in dir as a theme of this session.
+// Colors a file leaves out are taken from the default theme, and the theme
+// is named after the file unless it sets name itself. A missing dir is not
+// an error.
+func (s *Styles) LoadThemes(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, path := range paths {
+		t := themes[DefaultTheme]
+		t.Name = strings.TrimSuffix(filepath.Base(path), ".toml")
+		t.Light = Palette{}
+		if _, err := toml.DecodeFile(path, &t); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		t.Light = t.Light.or(defaultLight)
+		if s.custom == nil {
+			s.custom = map[string]Theme{}
+		}
+		s.custom[t.Name] = t
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("loading themes: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// or fills the colors p leaves empty from fallback
+func (p Palette) or(fallback Palette) Palette {
+	pick := func(c, f string) string {
+		if c == "" {
+			return f
+		}
+		return c
+	}
+	return Palette{
+		Primary:    pick(p.Primary, fallback.Primary),
+		Secondary:  pick(p.Secondary, fallback.Secondary),
+		Background: p.Background,
+		Text:       pick(p.Text, fallback.Text),
+		Muted:      pick(p.Muted, fallback.Muted),
+		Error:      pick(p.Error, fallback.Error),
+		Success:    pick(p.Success, fallback.Success),
+		Warning:    pick(p.Warning, fallback.Warning),
+		Accent:     pick(p.Accent, fallback.Accent),
+	}
+}